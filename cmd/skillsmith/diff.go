@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/monke/skillsmith/internal/installer"
+)
+
+var errNotInstalled = errors.New("item is not installed")
+
+// Diff colours. lipgloss drops them when stdout is not a terminal.
+var (
+	diffHeaderStyle = lipgloss.NewStyle().Bold(true)
+	diffHunkStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	diffDeleteStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	diffInsertStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+)
+
+func runDiff(_ *cobra.Command, args []string) error {
+	name := args[0]
+
+	mgr, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	item, err := mgr.GetItem(name)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errItemNotFound, name)
+	}
+
+	tools, scope, err := parseTarget(diffTool, diffScope)
+	if err != nil {
+		return err
+	}
+
+	doc := diffDocument{SchemaVersion: schemaVersion, Diffs: []toolDiffDocument{}}
+
+	for _, tool := range tools {
+		// Without --tool, compare every tool the item is installed for
+		if diffTool == "" && !item.IsCompatibleWith(tool) {
+			continue
+		}
+
+		text, diffErr := mgr.Diff(name, tool, scope)
+		if diffErr != nil {
+			if diffTool == "" && errors.Is(diffErr, installer.ErrNotInstalled) {
+				continue
+			}
+
+			return fmt.Errorf("diff %s (%s): %w", name, tool, diffErr)
+		}
+
+		doc.Diffs = append(doc.Diffs, toolDiffDocument{Tool: string(tool), Diff: text})
+	}
+
+	if len(doc.Diffs) == 0 {
+		return fmt.Errorf("%w: %s (%s)", errNotInstalled, name, scope)
+	}
+
+	w := os.Stdout
+
+	if isStructuredOutput() {
+		return writeDocument(w, doc)
+	}
+
+	for _, d := range doc.Diffs {
+		if d.Diff == "" {
+			mustWrite(w, fmt.Sprintf("%s (%s): no differences\n", name, d.Tool))
+
+			continue
+		}
+
+		mustWrite(w, colorDiff(d.Diff))
+	}
+
+	return nil
+}
+
+// colorDiff colours the lines of a unified diff.
+func colorDiff(text string) string {
+	var sb strings.Builder
+
+	for line := range strings.Lines(text) {
+		content := strings.TrimSuffix(line, "\n")
+
+		switch {
+		case strings.HasPrefix(content, "--- "), strings.HasPrefix(content, "+++ "):
+			content = diffHeaderStyle.Render(content)
+		case strings.HasPrefix(content, "@@"):
+			content = diffHunkStyle.Render(content)
+		case strings.HasPrefix(content, "-"):
+			content = diffDeleteStyle.Render(content)
+		case strings.HasPrefix(content, "+"):
+			content = diffInsertStyle.Render(content)
+		}
+
+		sb.WriteString(content + "\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/monke/skillsmith/internal/installer"
+	"github.com/monke/skillsmith/internal/loader"
+)
+
+// Process exit codes.
+const (
+	exitCodeError           = 1
+	exitCodeNotInstalled    = 3
+	exitCodeUpdateAvailable = 4
+	exitCodeModified        = 5
+)
+
+// Status check errors.
+var (
+	errStatusUnresolved      = errors.New("items could not be resolved")
+	errStatusNotInstalled    = errors.New("items are not installed")
+	errStatusUpdateAvailable = errors.New("items have updates available")
+	errStatusModified        = errors.New("items were modified locally")
+)
+
+// exitError is an error that makes the process exit with a specific code.
+type exitError struct {
+	code int
+	err  error
+}
+
+// Error implements error.
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// checkProjectStatus returns an exitError describing the most severe drift
+// in the project status results, if --check was given.
+func checkProjectStatus(results []loader.ProjectInstallResult) error {
+	if !projectStatusCheck {
+		return nil
+	}
+
+	var unresolved, notInstalled, updates, modified int
+
+	for _, r := range results {
+		switch {
+		case r.Error != nil:
+			unresolved++
+		case r.Skipped:
+			continue
+		case r.State == installer.StateNotInstalled:
+			notInstalled++
+		case r.State.IsModified():
+			modified++
+		case r.State.HasUpdate():
+			updates++
+		}
+	}
+
+	switch {
+	case unresolved > 0:
+		return &exitError{code: exitCodeError, err: fmt.Errorf("%w: %d", errStatusUnresolved, unresolved)}
+	case notInstalled > 0:
+		return &exitError{code: exitCodeNotInstalled, err: fmt.Errorf("%w: %d", errStatusNotInstalled, notInstalled)}
+	case modified > 0:
+		return &exitError{code: exitCodeModified, err: fmt.Errorf("%w: %d", errStatusModified, modified)}
+	case updates > 0:
+		return &exitError{code: exitCodeUpdateAvailable, err: fmt.Errorf("%w: %d", errStatusUpdateAvailable, updates)}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/monke/skillsmith/internal/installer"
+)
+
+var errNoBackups = errors.New("no backups found")
+
+func runHistory(_ *cobra.Command, args []string) error {
+	name := args[0]
+
+	mgr, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	item, err := mgr.GetItem(name)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errItemNotFound, name)
+	}
+
+	tools, scope, err := parseTarget(historyTool, historyScope)
+	if err != nil {
+		return err
+	}
+
+	doc := historyDocument{SchemaVersion: schemaVersion, Backups: []backupDocument{}}
+
+	for _, tool := range tools {
+		if historyTool == "" && !item.IsCompatibleWith(tool) {
+			continue
+		}
+
+		history, historyErr := mgr.History(name, tool, scope)
+		if historyErr != nil {
+			return fmt.Errorf("history of %s (%s): %w", name, tool, historyErr)
+		}
+
+		// Newest first
+		for _, b := range slices.Backward(history) {
+			doc.Backups = append(doc.Backups, newBackupDocument(string(tool), b))
+		}
+	}
+
+	w := os.Stdout
+
+	if isStructuredOutput() {
+		return writeDocument(w, doc)
+	}
+
+	if len(doc.Backups) == 0 {
+		mustWrite(w, fmt.Sprintf("No backups of %s (%s).\n", name, scope))
+
+		return nil
+	}
+
+	mustWrite(w, fmt.Sprintf("Backups of %s (%s), newest first:\n\n", name, scope))
+
+	for _, b := range doc.Backups {
+		mustWrite(w, fmt.Sprintf("  %s  %-8s before %-9s  %s\n",
+			b.ID, b.Tool, b.Reason, formatAge(time.Since(b.CreatedAt))))
+	}
+
+	mustWrite(w, "\nRestore one with 'skillsmith rollback "+name+" --to <id>'.\n")
+
+	return nil
+}
+
+func runRollback(_ *cobra.Command, args []string) error {
+	name := args[0]
+
+	mgr, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	item, err := mgr.GetItem(name)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errItemNotFound, name)
+	}
+
+	tools, scope, err := parseTarget(rollbackTool, rollbackScope)
+	if err != nil {
+		return err
+	}
+
+	doc := rollbackDocument{SchemaVersion: schemaVersion, Restored: []restoredDocument{}}
+
+	for _, tool := range tools {
+		// Without --tool, roll back every tool that has a matching backup
+		if rollbackTool == "" && !item.IsCompatibleWith(tool) {
+			continue
+		}
+
+		restored, path, rollbackErr := mgr.Rollback(name, tool, scope, rollbackTo)
+		if rollbackErr != nil {
+			if rollbackTool == "" &&
+				(errors.Is(rollbackErr, installer.ErrNoBackups) || errors.Is(rollbackErr, installer.ErrBackupNotFound)) {
+				continue
+			}
+
+			return fmt.Errorf("roll back %s (%s): %w", name, tool, rollbackErr)
+		}
+
+		doc.Restored = append(doc.Restored, restoredDocument{
+			Tool:   string(tool),
+			Path:   path,
+			Backup: newBackupDocument(string(tool), *restored),
+		})
+	}
+
+	if len(doc.Restored) == 0 {
+		if rollbackTo != "" {
+			return fmt.Errorf("%w: %s (%s) has no backup %s", errNoBackups, name, scope, rollbackTo)
+		}
+
+		return fmt.Errorf("%w: %s (%s)", errNoBackups, name, scope)
+	}
+
+	w := os.Stdout
+
+	if isStructuredOutput() {
+		return writeDocument(w, doc)
+	}
+
+	for _, r := range doc.Restored {
+		mustWrite(w, fmt.Sprintf("  [OK]   %s (%s): restored %s from before %s -> %s\n",
+			name, r.Tool, r.Backup.ID, r.Backup.Reason, r.Path))
+	}
+
+	return nil
+}
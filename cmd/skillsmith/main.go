@@ -6,11 +6,14 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 
 	"github.com/monke/skillsmith/internal/config"
+	"github.com/monke/skillsmith/internal/installer"
+	"github.com/monke/skillsmith/internal/lint"
 	"github.com/monke/skillsmith/internal/loader"
 	"github.com/monke/skillsmith/internal/project"
 	"github.com/monke/skillsmith/internal/registry"
@@ -25,14 +28,26 @@ var (
 	errUnknownItemType  = errors.New("unknown item type")
 	errItemNotInProject = errors.New("item is not in the project")
 	errInstallFailed    = errors.New("some items failed to install")
+	errNoLockFile       = errors.New("no lock file found, run 'skillsmith project install' without --frozen first")
+	errUpdateFailed     = errors.New("registries failed to update")
+	errLintFailed       = errors.New("lint found errors")
 )
 
 var version = "dev"
 
 func main() {
+	installer.Version = version
+
 	err := rootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		mustWrite(os.Stderr, fmt.Sprintf("Error: %v\n", err))
+
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
+
+		os.Exit(exitCodeError)
 	}
 }
 
@@ -40,12 +55,40 @@ var rootCmd = &cobra.Command{
 	Use:   "skillsmith",
 	Short: "Install agents and skills for AI coding tools",
 	Long: `skillsmith is a TUI for browsing, previewing, and installing
-agents, subagents, and skills for AI coding tools like OpenCode and Claude Code.
+agents, subagents, and skills for AI coding tools like OpenCode, Claude Code, Cursor and
+GitHub Copilot.
+
+Run 'skillsmith tui' to launch the interactive browser.
+
+Use --output json or --output yaml for machine-readable output from
+'list', 'registry list', 'project list', 'project status' and 'project install'.
+
+Git registries are fetched at most once per fetch_ttl (default 15m, set in
+~/.config/skillsmith/config.yaml). Use --offline or SKILLSMITH_OFFLINE=1 to
+load from the local cache only.
+
+Items that declare vars: in their frontmatter have their body rendered as a
+Go template. Set values under vars: in config.yaml or .skillsmith.yaml; project
+values take precedence for local installs, global installs only use
+config.yaml, and declared defaults fill in the rest.`,
+	Version:           version,
+	SilenceUsage:      true,
+	SilenceErrors:     true,
+	PersistentPreRunE: preRun,
+}
+
+// preRun validates global flags and applies them before any command runs.
+func preRun(cmd *cobra.Command, args []string) error {
+	err := validateOutputFormat(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	if offlineMode {
+		loader.SetOffline(true)
+	}
 
-Run 'skillsmith tui' to launch the interactive browser.`,
-	Version:       version,
-	SilenceUsage:  true,
-	SilenceErrors: true,
+	return nil
 }
 
 var tuiCmd = &cobra.Command{
@@ -62,7 +105,14 @@ Press '?' for help.`,
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List available agents and skills",
-	RunE:  runList,
+	Long: `List available agents and skills from all registries.
+
+When several registries define an item with the same name, the last one wins:
+project registries override global registries, which override builtin items.
+Use --shadowed to show only items that override another registry's definition.
+A shadowed definition can still be used with a qualified name like
+'builtin/committing'.`,
+	RunE: runList,
 }
 
 var registryCmd = &cobra.Command{
@@ -83,7 +133,8 @@ var registryAddCmd = &cobra.Command{
 	Long: `Add a local directory as a registry source.
 
 The directory should contain 'agents/' and/or 'skills/' subdirectories
-with markdown files using YAML frontmatter.`,
+with markdown files using YAML frontmatter. Skills can also be directories
+(skills/<name>/SKILL.md) bundling scripts, templates and reference docs.`,
 	Args: cobra.ExactArgs(2), //nolint:mnd // name and path
 	RunE: runRegistryAdd,
 }
@@ -104,12 +155,161 @@ The repository will be cloned to a local cache and used as a source
 for agents and skills. The repository should contain 'agents/' and/or
 'skills/' subdirectories with markdown files using YAML frontmatter.
 
+Use --ref to pin the registry to a tag, branch or commit SHA. Without it,
+the repository's default branch is tracked.
+
 Example:
-  skillsmith registry add-git team-skills https://github.com/myteam/skills.git`,
+  skillsmith registry add-git team-skills https://github.com/myteam/skills.git
+  skillsmith registry add-git team-skills https://github.com/myteam/skills.git --ref v1.2.0`,
 	Args: cobra.ExactArgs(2), //nolint:mnd // name and url
 	RunE: runRegistryAddGit,
 }
 
+var registryUpdateCmd = &cobra.Command{
+	Use:   "update [name...]",
+	Short: "Fetch Git registries",
+	Long: `Fetch the latest content of Git registries.
+
+Without arguments, all enabled Git registries are updated. For each registry
+the commit before and after the update is reported. Registries pinned with
+--ref are re-fetched and checked out at their pinned ref.`,
+	RunE: runRegistryUpdate,
+}
+
+var registryDiagnosticsCmd = &cobra.Command{
+	Use:   "diagnostics [name...]",
+	Short: "Show files that were skipped while loading registries",
+	Long: `Show the agent and skill files that could not be parsed and were skipped
+while loading registries, with the file, line and reason.
+
+Without arguments, diagnostics of all registries are shown.`,
+	RunE: runRegistryDiagnostics,
+}
+
+var registryCleanCmd = &cobra.Command{
+	Use:   "clean [name...]",
+	Short: "Remove cached Git registries",
+	Long: `Remove the local cache of Git registries.
+
+Without arguments, the whole registry cache is removed, including caches
+of registries that are no longer configured. Caches are re-created the
+next time skills are loaded.`,
+	RunE: runRegistryClean,
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [path]",
+	Short: "Validate a registry directory",
+	Long: `Validate the agents and skills in a registry directory before publishing it.
+
+Files are parsed strictly and checked for:
+  - missing name or description
+  - names that don't match the file name or break the naming rules
+    (lowercase letters, digits and single hyphens, at most 64 characters)
+  - unknown frontmatter keys and unknown tools in compatibility
+  - duplicate names across agents and skills
+  - empty bodies and descriptions longer than 1024 characters
+  - bundles without members, and requirements not defined in the registry
+  - invalid variable names and body templates that don't render
+
+The command exits non-zero if any errors are found. Warnings don't fail the run.
+Defaults to the current directory.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLint,
+}
+
+var newCmd = &cobra.Command{
+	Use:   "new <skill|agent> <name>",
+	Short: "Create a new skill or agent in a local registry",
+	Long: `Create a new skill or agent in a local registry from a template.
+
+Values not given as flags are prompted for when running in a terminal,
+unless structured output is requested with --output.
+The generated file is validated with the same rules as 'skillsmith lint'.
+
+Use --dir to create a directory-form skill (skills/<name>/SKILL.md) that can
+bundle scripts, templates and reference docs.
+
+Example:
+  skillsmith new skill writing-rust --registry team --description "Rust conventions"
+  skillsmith new agent release-manager --registry team --compatibility claude,opencode`,
+	Args:      cobra.ExactArgs(2), //nolint:mnd // type and name
+	ValidArgs: []string{string(registry.ItemTypeSkill), string(registry.ItemTypeAgent)},
+	RunE:      runNew,
+}
+
+var updateCmd = &cobra.Command{
+	Use:   "update [name...]",
+	Short: "Update installed agents and skills",
+	Long: `Update installed agents and skills that have a newer registry version.
+
+Without arguments, every installed item with an update is updated. Items are
+updated for all tools unless --tool is given, in the local scope unless
+--scope global is given.
+
+Locally modified items are skipped. With --merge, the update is merged with
+your changes instead: changes to different lines are combined, and lines
+changed on both sides are kept between conflict markers (<<<<<<< local,
+=======, >>>>>>> registry) for you to resolve. Merging needs the version the
+item was installed with, which is recorded from this version on.
+
+Example:
+  skillsmith update
+  skillsmith update writing-go --merge
+  skillsmith update --tool claude --scope global`,
+	RunE: runUpdate,
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <name>",
+	Short: "Show changes between an installed item and the registry",
+	Long: `Show a unified diff from the installed file of an item to the version the
+registry would install now. Lines starting with - are only in the installed
+file, lines starting with + are only in the registry version.
+
+Without --tool, the item is compared for every tool it is installed for.
+Bundled files of directory-form skills are not compared.
+
+Example:
+  skillsmith diff writing-go
+  skillsmith diff writing-go --tool claude --scope global`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDiff,
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history <name>",
+	Short: "List backups of an installed item",
+	Long: `List the backups of an item, newest first.
+
+Whenever an installed item is overwritten by a forced install, an update or a
+merge, or removed by an uninstall, the previous version is backed up first,
+including bundled files. The last 10 backups are kept per item, tool and
+scope. Restore one with 'skillsmith rollback'.
+
+Example:
+  skillsmith history writing-go
+  skillsmith history writing-go --tool claude --scope global`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHistory,
+}
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback <name>",
+	Short: "Restore a backup of an installed item",
+	Long: `Restore the latest backup of an item, or the one given with --to.
+
+The version that is replaced is backed up in turn, so a rollback can be
+undone by rolling back again. Without --tool, the item is rolled back for
+every tool that has a backup.
+
+Example:
+  skillsmith rollback writing-go
+  skillsmith rollback writing-go --tool claude --to 20260102-150405.000000`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRollback,
+}
+
 // Project commands.
 var projectCmd = &cobra.Command{
 	Use:   "project",
@@ -130,37 +330,69 @@ This file defines which skills and agents should be installed for this project.`
 }
 
 var projectAddCmd = &cobra.Command{
-	Use:   "add <name>",
-	Short: "Add a skill or agent to the project",
-	Long: `Add a skill or agent to the project's .skillsmith.yaml file.
+	Use:   "add <[registry/]name[@ref]>",
+	Short: "Add a skill, agent or bundle to the project",
+	Long: `Add a skill, agent or bundle to the project's .skillsmith.yaml file.
+
+The item will be added to the skills, agents or bundles list based on its type.
 
-The item will be added to either the skills or agents list based on its type.`,
+Prefix the name with a registry to pin the item to that registry, even if
+another registry overrides it, and append @ref to require a Git registry to
+be at that tag, branch or commit.
+
+Example:
+  skillsmith project add writing-go
+  skillsmith project add team-skills/writing-go
+  skillsmith project add team-skills/writing-go@v1.2.0`,
 	Args: cobra.ExactArgs(1),
 	RunE: runProjectAdd,
 }
 
 var projectRemoveCmd = &cobra.Command{
 	Use:   "remove <name>",
-	Short: "Remove a skill or agent from the project",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runProjectRemove,
+	Short: "Remove a skill, agent or bundle from the project",
+	Long: `Remove a skill, agent or bundle from the project's .skillsmith.yaml file.
+
+The name matches the item however it is listed, so 'skillsmith project remove
+writing-go' also removes team-skills/writing-go@v1.2.0.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runProjectRemove,
 }
 
 var projectInstallCmd = &cobra.Command{
 	Use:   "install",
 	Short: "Install all project skills and agents",
-	Long: `Install all skills and agents defined in .skillsmith.yaml.
+	Long: `Install all skills, agents and bundles defined in .skillsmith.yaml.
 
 Items are installed for all compatible tools, or only for tools specified
-in the project config.`,
+in the project config.
+
+Items listed under requires: in an item's frontmatter are installed first,
+and bundles install every item they require. Dependency cycles and missing
+requirements are reported per item.
+
+After a successful install, the resolved source, Git commit and content hash
+of every item are recorded in .skillsmith.lock next to .skillsmith.yaml.
+Use --frozen to install exactly what the lock file records and refuse
+anything that doesn't match it.`,
 	RunE: runProjectInstall,
 }
 
 var projectStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show project installation status",
-	Long:  `Show the installation status of all skills and agents in the project.`,
-	RunE:  runProjectStatus,
+	Long: `Show the installation status of all skills and agents in the project.
+Installed items show the registry and commit they were installed from, when,
+and by which skillsmith version.
+
+Use --check in CI to fail when installed items have drifted from .skillsmith.yaml.
+The exit code reports the most severe problem found:
+
+  1  an item could not be resolved (e.g. not found in any registry)
+  3  an item is not installed
+  4  an update is available for an installed item
+  5  an installed item was modified locally`,
+	RunE: runProjectStatus,
 }
 
 var projectListCmd = &cobra.Command{
@@ -170,18 +402,50 @@ var projectListCmd = &cobra.Command{
 }
 
 // Flags.
-var projectInstallForce bool
+var (
+	offlineMode          bool
+	listShadowed         bool
+	projectInstallForce  bool
+	projectInstallFrozen bool
+	projectStatusCheck   bool
+	registryAddGitRef    string
+	newRegistry          string
+	newDescription       string
+	newCategory          string
+	newCompatibility     []string
+	newTags              []string
+	newDir               bool
+	updateTool           string
+	updateScope          string
+	updateMerge          bool
+	diffTool             string
+	diffScope            string
+	historyTool          string
+	historyScope         string
+	rollbackTool         string
+	rollbackScope        string
+	rollbackTo           string
+)
 
 func setupCommands() {
 	rootCmd.AddCommand(tuiCmd)
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(registryCmd)
 	rootCmd.AddCommand(projectCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(newCmd)
+	rootCmd.AddCommand(updateCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(rollbackCmd)
 
 	registryCmd.AddCommand(registryListCmd)
 	registryCmd.AddCommand(registryAddCmd)
 	registryCmd.AddCommand(registryRemoveCmd)
 	registryCmd.AddCommand(registryAddGitCmd)
+	registryCmd.AddCommand(registryUpdateCmd)
+	registryCmd.AddCommand(registryCleanCmd)
+	registryCmd.AddCommand(registryDiagnosticsCmd)
 
 	projectCmd.AddCommand(projectInitCmd)
 	projectCmd.AddCommand(projectAddCmd)
@@ -191,7 +455,30 @@ func setupCommands() {
 	projectCmd.AddCommand(projectListCmd)
 
 	// Flags
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputText, "Output format: text, json or yaml")
+	rootCmd.PersistentFlags().BoolVar(&offlineMode, "offline", false, "Load Git registries from the local cache only")
+	listCmd.Flags().BoolVar(&listShadowed, "shadowed", false, "Show only items that override another registry's definition")
 	projectInstallCmd.Flags().BoolVarP(&projectInstallForce, "force", "f", false, "Force reinstall even if up to date")
+	projectInstallCmd.Flags().BoolVar(&projectInstallFrozen, "frozen", false, "Only install items that match the lock file")
+	projectStatusCmd.Flags().BoolVar(&projectStatusCheck, "check", false, "Exit non-zero if any item is not up to date")
+	registryAddGitCmd.Flags().StringVar(&registryAddGitRef, "ref", "", "Tag, branch or commit SHA to pin the registry to")
+	newCmd.Flags().StringVarP(&newRegistry, "registry", "r", "", "Local registry to create the item in (required)")
+	newCmd.Flags().StringVarP(&newDescription, "description", "d", "", "Short description of the item")
+	newCmd.Flags().StringVar(&newCategory, "category", "", "Category for grouping in the UI")
+	newCmd.Flags().StringSliceVar(&newCompatibility, "compatibility", nil, "Compatible tools (default: all builtin tools)")
+	newCmd.Flags().StringSliceVar(&newTags, "tags", nil, "Tags for filtering")
+	newCmd.Flags().BoolVar(&newDir, "dir", false, "Create a directory-form skill")
+	_ = newCmd.MarkFlagRequired("registry")
+	updateCmd.Flags().StringVarP(&updateTool, "tool", "t", "", "Only update items installed for this tool")
+	updateCmd.Flags().StringVarP(&updateScope, "scope", "s", string(config.ScopeLocal), "Scope to update: local or global")
+	updateCmd.Flags().BoolVarP(&updateMerge, "merge", "m", false, "Merge updates into locally modified items")
+	diffCmd.Flags().StringVarP(&diffTool, "tool", "t", "", "Only compare the item installed for this tool")
+	diffCmd.Flags().StringVarP(&diffScope, "scope", "s", string(config.ScopeLocal), "Scope to compare: local or global")
+	historyCmd.Flags().StringVarP(&historyTool, "tool", "t", "", "Only list backups for this tool")
+	historyCmd.Flags().StringVarP(&historyScope, "scope", "s", string(config.ScopeLocal), "Scope to list: local or global")
+	rollbackCmd.Flags().StringVarP(&rollbackTool, "tool", "t", "", "Only roll back the item installed for this tool")
+	rollbackCmd.Flags().StringVarP(&rollbackScope, "scope", "s", string(config.ScopeLocal), "Scope to roll back: local or global")
+	rollbackCmd.Flags().StringVar(&rollbackTo, "to", "", "ID of the backup to restore (default: latest)")
 }
 
 //nolint:gochecknoinits // cobra requires init for command setup
@@ -199,10 +486,38 @@ func init() {
 	setupCommands()
 }
 
-func runTUI(_ *cobra.Command, _ []string) error {
+// newManager loads the registries and warns about sources that failed to load.
+func newManager() (*loader.Manager, error) {
 	mgr, err := loader.NewManager()
 	if err != nil {
-		return fmt.Errorf("initialize manager: %w", err)
+		return nil, fmt.Errorf("initialize manager: %w", err)
+	}
+
+	for _, loadErr := range mgr.LoadErrors() {
+		mustWrite(os.Stderr, fmt.Sprintf("Warning: %v\n", loadErr))
+	}
+
+	for _, status := range mgr.SourceStatuses() {
+		if len(status.Diagnostics) > 0 {
+			mustWrite(os.Stderr, fmt.Sprintf("Warning: source %s: skipped %d invalid files, see 'skillsmith registry diagnostics %s'\n",
+				status.Name, len(status.Diagnostics), status.Name))
+		}
+	}
+
+	return mgr, nil
+}
+
+// printWarnings writes the problems that didn't stop the last operations of mgr to stderr.
+func printWarnings(mgr *loader.Manager) {
+	for _, warning := range mgr.Warnings() {
+		mustWrite(os.Stderr, fmt.Sprintf("Warning: %v\n", warning))
+	}
+}
+
+func runTUI(_ *cobra.Command, _ []string) error {
+	mgr, err := newManager()
+	if err != nil {
+		return err
 	}
 
 	model := tui.NewModel(mgr)
@@ -217,18 +532,52 @@ func runTUI(_ *cobra.Command, _ []string) error {
 }
 
 func runList(_ *cobra.Command, _ []string) error {
-	mgr, err := loader.NewManager()
+	mgr, err := newManager()
 	if err != nil {
-		return fmt.Errorf("initialize manager: %w", err)
+		return err
 	}
 
 	w := os.Stdout
 
+	if isStructuredOutput() {
+		return writeDocument(w, buildListDocument(mgr, listShadowed))
+	}
+
+	if listShadowed {
+		writeShadowedOutput(w, mgr)
+
+		return nil
+	}
+
 	writeListOutput(w, mgr)
 
 	return nil
 }
 
+func writeShadowedOutput(w io.Writer, mgr *loader.Manager) {
+	found := false
+
+	for _, item := range mgr.Registry().Items {
+		overrides := mgr.OverriddenSources(item.Name)
+		if len(overrides) == 0 {
+			continue
+		}
+
+		if !found {
+			mustWrite(w, "Overridden agents and skills:\n\n")
+
+			found = true
+		}
+
+		mustWrite(w, fmt.Sprintf("  - %s (%s): %s overrides %s\n",
+			item.Name, item.Type, item.Source, strings.Join(overrides, ", ")))
+	}
+
+	if !found {
+		mustWrite(w, "No items are overridden.\n")
+	}
+}
+
 func writeListOutput(w io.Writer, mgr *loader.Manager) {
 	mustWrite(w, "Available agents and skills:\n\n")
 
@@ -259,6 +608,19 @@ func writeListOutput(w io.Writer, mgr *loader.Manager) {
 
 		mustWrite(w, "\n")
 	}
+
+	// Bundles aren't installed per tool, so they're listed regardless of compatibility
+	bundles := mgr.Registry().ByType(registry.ItemTypeBundle)
+
+	if len(bundles) > 0 {
+		mustWrite(w, "  Bundles:\n")
+
+		for _, item := range bundles {
+			mustWrite(w, "    - "+item.Name+": "+item.Description+" ("+strings.Join(item.Requires, ", ")+")\n")
+		}
+
+		mustWrite(w, "\n")
+	}
 }
 
 func formatCompatibility(tools []registry.Tool) string {
@@ -266,12 +628,16 @@ func formatCompatibility(tools []registry.Tool) string {
 		return ""
 	}
 
-	names := make([]string, len(tools))
-	for i, t := range tools {
-		names[i] = string(t)
+	return "[" + strings.Join(toolNames(tools), ", ") + "]"
+}
+
+// formatGitURL formats a Git URL with its pinned ref, if any.
+func formatGitURL(url, ref string) string {
+	if ref == "" {
+		return url
 	}
 
-	return "[" + strings.Join(names, ", ") + "]"
+	return url + "@" + ref
 }
 
 func mustWrite(w io.Writer, s string) {
@@ -279,6 +645,7 @@ func mustWrite(w io.Writer, s string) {
 }
 
 func runRegistryList(_ *cobra.Command, _ []string) error {
+	// Load failures are part of the listing, so don't warn about them separately
 	mgr, err := loader.NewManager()
 	if err != nil {
 		return fmt.Errorf("initialize manager: %w", err)
@@ -291,13 +658,14 @@ func runRegistryList(_ *cobra.Command, _ []string) error {
 
 	w := os.Stdout
 
+	if isStructuredOutput() {
+		return writeDocument(w, buildRegistryListDocument(registries))
+	}
+
 	mustWrite(w, "Configured registries:\n\n")
 
 	for _, reg := range registries {
-		status := ""
-		if !reg.Enabled {
-			status = " (disabled)"
-		}
+		status := formatRegistryStatus(reg)
 
 		switch reg.Type {
 		case "builtin":
@@ -305,7 +673,7 @@ func runRegistryList(_ *cobra.Command, _ []string) error {
 		case "local":
 			mustWrite(w, fmt.Sprintf("  %s: %s%s\n", reg.Name, reg.Path, status))
 		case "git":
-			mustWrite(w, fmt.Sprintf("  %s: %s%s\n", reg.Name, reg.URL, status))
+			mustWrite(w, fmt.Sprintf("  %s: %s%s\n", reg.Name, formatGitURL(reg.URL, reg.Ref), status))
 		}
 	}
 
@@ -318,10 +686,65 @@ func runRegistryList(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
+// formatRegistryStatus renders the health of a registry for 'registry list'.
+func formatRegistryStatus(reg loader.RegistryInfo) string {
+	switch {
+	case !reg.Enabled:
+		return " (disabled)"
+	case reg.Status == nil:
+		return ""
+	case !reg.Status.OK():
+		return fmt.Sprintf(" [failed after %s: %v]", formatLoadTime(reg.Status.Duration), reg.Status.Error)
+	}
+
+	status := fmt.Sprintf(" [ok, %d items", reg.Status.ItemCount)
+	if reg.Status.ItemCount == 1 {
+		status = " [ok, 1 item"
+	}
+
+	status += " in " + formatLoadTime(reg.Status.Duration)
+
+	if len(reg.Status.Diagnostics) > 0 {
+		status += fmt.Sprintf(", %d skipped", len(reg.Status.Diagnostics))
+	}
+
+	if !reg.Status.LastUpdated.IsZero() {
+		status += ", updated " + formatAge(time.Since(reg.Status.LastUpdated))
+	}
+
+	return status + "]"
+}
+
+// formatLoadTime renders how long a registry took to load, to the millisecond,
+// or to the microsecond if it took less than that.
+func formatLoadTime(d time.Duration) string {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond).String()
+	}
+
+	return d.Round(time.Millisecond).String()
+}
+
+// formatAge renders a duration as a short relative age like "5m ago".
+func formatAge(age time.Duration) string {
+	const day = 24 * time.Hour
+
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age/time.Minute))
+	case age < day:
+		return fmt.Sprintf("%dh ago", int(age/time.Hour))
+	default:
+		return fmt.Sprintf("%dd ago", int(age/day))
+	}
+}
+
 func runRegistryAdd(_ *cobra.Command, args []string) error {
-	mgr, err := loader.NewManager()
+	mgr, err := newManager()
 	if err != nil {
-		return fmt.Errorf("initialize manager: %w", err)
+		return err
 	}
 
 	name := args[0]
@@ -332,49 +755,317 @@ func runRegistryAdd(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("add registry: %w", err)
 	}
 
+	if isStructuredOutput() {
+		return writeRegistryChange(mgr, "added", name)
+	}
+
 	mustWrite(os.Stdout, fmt.Sprintf("Added registry %q from %s\n", name, path))
 
 	return nil
 }
 
 func runRegistryRemove(_ *cobra.Command, args []string) error {
-	mgr, err := loader.NewManager()
+	mgr, err := newManager()
 	if err != nil {
-		return fmt.Errorf("initialize manager: %w", err)
+		return err
 	}
 
 	name := args[0]
 
+	// Describe the registry before it is gone from the config
+	regDoc := registryDocument{Name: name}
+	if reg, ok := findRegistryInfo(mgr, name); ok {
+		regDoc = newRegistryDocument(reg)
+	}
+
 	err = mgr.RemoveRegistry(name)
 	if err != nil {
 		return fmt.Errorf("remove registry: %w", err)
 	}
 
+	if isStructuredOutput() {
+		return writeDocument(os.Stdout, registryChangeDocument{
+			SchemaVersion: schemaVersion,
+			Action:        "removed",
+			Registry:      regDoc,
+		})
+	}
+
 	mustWrite(os.Stdout, fmt.Sprintf("Removed registry %q\n", name))
 
 	return nil
 }
 
 func runRegistryAddGit(_ *cobra.Command, args []string) error {
-	mgr, err := loader.NewManager()
+	mgr, err := newManager()
 	if err != nil {
-		return fmt.Errorf("initialize manager: %w", err)
+		return err
 	}
 
 	name := args[0]
 	url := args[1]
 
-	err = mgr.AddGitRegistry(name, url)
+	err = mgr.AddGitRegistry(name, url, registryAddGitRef)
 	if err != nil {
 		return fmt.Errorf("add git registry: %w", err)
 	}
 
-	mustWrite(os.Stdout, fmt.Sprintf("Added git registry %q from %s\n", name, url))
+	if isStructuredOutput() {
+		return writeRegistryChange(mgr, "added", name)
+	}
+
+	if registryAddGitRef != "" {
+		mustWrite(os.Stdout, fmt.Sprintf("Added git registry %q from %s@%s\n", name, url, registryAddGitRef))
+	} else {
+		mustWrite(os.Stdout, fmt.Sprintf("Added git registry %q from %s\n", name, url))
+	}
 	mustWrite(os.Stdout, "The repository will be cloned when you next load skills.\n")
 
 	return nil
 }
 
+// findRegistryInfo returns the configured registry with the given name.
+func findRegistryInfo(mgr *loader.Manager, name string) (loader.RegistryInfo, bool) {
+	registries, err := mgr.ListRegistries()
+	if err != nil {
+		return loader.RegistryInfo{}, false
+	}
+
+	for _, reg := range registries {
+		if reg.Name == name {
+			return reg, true
+		}
+	}
+
+	return loader.RegistryInfo{}, false
+}
+
+// writeRegistryChange writes the structured output of a command that changed a registry.
+func writeRegistryChange(mgr *loader.Manager, action, name string) error {
+	regDoc := registryDocument{Name: name}
+	if reg, ok := findRegistryInfo(mgr, name); ok {
+		regDoc = newRegistryDocument(reg)
+	}
+
+	return writeDocument(os.Stdout, registryChangeDocument{
+		SchemaVersion: schemaVersion,
+		Action:        action,
+		Registry:      regDoc,
+	})
+}
+
+func runRegistryUpdate(_ *cobra.Command, args []string) error {
+	updates, err := loader.UpdateRegistries(args)
+	if err != nil {
+		return fmt.Errorf("update registries: %w", err)
+	}
+
+	failed := 0
+
+	for _, update := range updates {
+		if update.Error != nil {
+			failed++
+		}
+	}
+
+	w := os.Stdout
+
+	if isStructuredOutput() {
+		err = writeDocument(w, buildRegistryUpdateDocument(updates))
+		if err != nil {
+			return err
+		}
+	} else {
+		if len(updates) == 0 {
+			mustWrite(w, "No Git registries configured.\n")
+
+			return nil
+		}
+
+		mustWrite(w, "Updating registries:\n\n")
+
+		for _, update := range updates {
+			switch {
+			case update.Error != nil:
+				mustWrite(w, fmt.Sprintf("  [FAIL] %s: %v\n", update.Name, update.Error))
+			case update.OldCommit == "":
+				mustWrite(w, fmt.Sprintf("  [NEW]  %s: cloned at %s\n", update.Name, shortCommit(update.NewCommit)))
+			case update.OldCommit == update.NewCommit:
+				mustWrite(w, fmt.Sprintf("  [OK]   %s: up to date (%s)\n", update.Name, shortCommit(update.NewCommit)))
+			default:
+				mustWrite(w, fmt.Sprintf("  [UPD]  %s: %s -> %s\n",
+					update.Name, shortCommit(update.OldCommit), shortCommit(update.NewCommit)))
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%w: %d", errUpdateFailed, failed)
+	}
+
+	return nil
+}
+
+func runRegistryDiagnostics(_ *cobra.Command, args []string) error {
+	// Load failures and skipped files are what this command reports
+	mgr, err := loader.NewManager()
+	if err != nil {
+		return fmt.Errorf("initialize manager: %w", err)
+	}
+
+	statuses := mgr.SourceStatuses()
+
+	if len(args) > 0 {
+		statuses = make([]loader.SourceStatus, 0, len(args))
+
+		for _, name := range args {
+			status := findSourceStatus(mgr.SourceStatuses(), name)
+			if status == nil {
+				return fmt.Errorf("%w: %s", loader.ErrRegistryNotFound, name)
+			}
+
+			statuses = append(statuses, *status)
+		}
+	}
+
+	w := os.Stdout
+
+	if isStructuredOutput() {
+		return writeDocument(w, buildDiagnosticsDocument(statuses))
+	}
+
+	found := false
+
+	for _, status := range statuses {
+		if status.OK() && len(status.Diagnostics) == 0 {
+			continue
+		}
+
+		found = true
+
+		mustWrite(w, status.Name+":\n")
+
+		if !status.OK() {
+			mustWrite(w, fmt.Sprintf("  failed to load: %v\n", status.Error))
+		}
+
+		for _, diag := range status.Diagnostics {
+			mustWrite(w, fmt.Sprintf("  %s\n", diag))
+		}
+	}
+
+	if !found {
+		mustWrite(w, "No problems found.\n")
+	}
+
+	return nil
+}
+
+// findSourceStatus returns the status of the source with the given name, or nil.
+func findSourceStatus(statuses []loader.SourceStatus, name string) *loader.SourceStatus {
+	for i := range statuses {
+		if statuses[i].Name == name {
+			return &statuses[i]
+		}
+	}
+
+	return nil
+}
+
+func runRegistryClean(_ *cobra.Command, args []string) error {
+	cleaned, err := loader.CleanRegistries(args)
+	if err != nil {
+		return fmt.Errorf("clean registries: %w", err)
+	}
+
+	if isStructuredOutput() {
+		doc := registryCleanDocument{SchemaVersion: schemaVersion, All: len(args) == 0, Cleaned: cleaned}
+		if doc.Cleaned == nil {
+			doc.Cleaned = []string{}
+		}
+
+		return writeDocument(os.Stdout, doc)
+	}
+
+	if len(args) == 0 {
+		mustWrite(os.Stdout, "Removed all cached registries\n")
+
+		return nil
+	}
+
+	for _, name := range cleaned {
+		mustWrite(os.Stdout, fmt.Sprintf("Removed cache of registry %q\n", name))
+	}
+
+	return nil
+}
+
+// formatProvenance describes where an installed item came from.
+func formatProvenance(installed *installer.InstalledItem) string {
+	date := installed.InstalledAt.Local().Format(time.DateTime)
+
+	// Items installed before provenance was recorded only have a date
+	if installed.Source == "" {
+		return "installed " + date + ", source not recorded"
+	}
+
+	source := installed.Source
+	if installed.Commit != "" {
+		source += "@" + shortCommit(installed.Commit)
+	}
+
+	return fmt.Sprintf("from %s, installed %s by skillsmith %s", source, date, installed.Version)
+}
+
+// shortCommit abbreviates a commit SHA for display.
+func shortCommit(commit string) string {
+	const shortLen = 7
+
+	if len(commit) > shortLen {
+		return commit[:shortLen]
+	}
+
+	return commit
+}
+
+func runLint(_ *cobra.Command, args []string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	report, err := lint.Run(path)
+	if err != nil {
+		return fmt.Errorf("lint: %w", err)
+	}
+
+	w := os.Stdout
+
+	if isStructuredOutput() {
+		err = writeDocument(w, buildLintDocument(path, report))
+		if err != nil {
+			return err
+		}
+	} else {
+		for _, finding := range report.Findings {
+			mustWrite(w, finding.String()+"\n")
+		}
+
+		if len(report.Findings) > 0 {
+			mustWrite(w, "\n")
+		}
+
+		mustWrite(w, fmt.Sprintf("%d items checked, %d errors, %d warnings\n",
+			report.Items, report.Count(lint.SeverityError), report.Count(lint.SeverityWarning)))
+	}
+
+	if report.HasErrors() {
+		return fmt.Errorf("%w: %d", errLintFailed, report.Count(lint.SeverityError))
+	}
+
+	return nil
+}
+
 // Project command implementations.
 
 func runProjectInit(_ *cobra.Command, _ []string) error {
@@ -395,6 +1086,13 @@ func runProjectInit(_ *cobra.Command, _ []string) error {
 
 	_ = cfg // unused for now
 
+	if isStructuredOutput() {
+		return writeDocument(os.Stdout, projectInitDocument{
+			SchemaVersion: schemaVersion,
+			Path:          project.GetConfigPath(cwd),
+		})
+	}
+
 	mustWrite(os.Stdout, fmt.Sprintf("Created %s\n", project.GetConfigPath(cwd)))
 	mustWrite(os.Stdout, "\nNext steps:\n")
 	mustWrite(os.Stdout, "  skillsmith project add <skill>   Add skills to the project\n")
@@ -417,30 +1115,54 @@ func runProjectAdd(_ *cobra.Command, args []string) error {
 	}
 
 	// Load manager to check if item exists
-	mgr, err := loader.NewManager()
+	mgr, err := newManager()
 	if err != nil {
-		return fmt.Errorf("initialize manager: %w", err)
+		return err
 	}
 
 	// Find the item to determine its type
-	item, err := mgr.GetItem(name)
+	item, err := mgr.ResolveProjectItem(name)
 	if err != nil {
-		return fmt.Errorf("%w: %s", errItemNotFound, name)
+		if errors.Is(err, loader.ErrItemNotFound) {
+			return fmt.Errorf("%w: %s", errItemNotFound, name)
+		}
+
+		return fmt.Errorf("resolve %s: %w", name, err)
 	}
 
-	// Add to appropriate list
-	var added bool
+	// Add to appropriate list, replacing another reference to the same item
+	var (
+		replaced string
+		added    bool
+	)
 
 	switch item.Type {
 	case registry.ItemTypeSkill:
-		added = cfg.AddSkill(name)
+		replaced, added = cfg.AddSkill(name)
 	case registry.ItemTypeAgent:
-		added = cfg.AddAgent(name)
+		replaced, added = cfg.AddAgent(name)
+	case registry.ItemTypeBundle:
+		replaced, added = cfg.AddBundle(name)
 	default:
 		return fmt.Errorf("%w: %s", errUnknownItemType, item.Type)
 	}
 
+	doc := projectChangeDocument{
+		SchemaVersion: schemaVersion,
+		Action:        "added",
+		Name:          name,
+		Type:          string(item.Type),
+		Replaced:      replaced,
+		Path:          project.GetConfigPath(projectDir),
+	}
+
 	if !added {
+		if isStructuredOutput() {
+			doc.Action = "unchanged"
+
+			return writeDocument(os.Stdout, doc)
+		}
+
 		mustWrite(os.Stdout, fmt.Sprintf("%s %q is already in the project\n", item.Type, name))
 
 		return nil
@@ -452,6 +1174,20 @@ func runProjectAdd(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("save project: %w", err)
 	}
 
+	if isStructuredOutput() {
+		if replaced != "" {
+			doc.Action = "replaced"
+		}
+
+		return writeDocument(os.Stdout, doc)
+	}
+
+	if replaced != "" {
+		mustWrite(os.Stdout, fmt.Sprintf("Replaced %s %q with %q in project\n", item.Type, replaced, name))
+
+		return nil
+	}
+
 	mustWrite(os.Stdout, fmt.Sprintf("Added %s %q to project\n", item.Type, name))
 
 	return nil
@@ -469,11 +1205,12 @@ func runProjectRemove(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("load project: %w", err)
 	}
 
-	// Try to remove from both lists
+	// Try to remove from all lists
 	removedSkill := cfg.RemoveSkill(name)
 	removedAgent := cfg.RemoveAgent(name)
+	removedBundle := cfg.RemoveBundle(name)
 
-	if !removedSkill && !removedAgent {
+	if !removedSkill && !removedAgent && !removedBundle {
 		return fmt.Errorf("%w: %s", errItemNotInProject, name)
 	}
 
@@ -482,6 +1219,26 @@ func runProjectRemove(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("save project: %w", err)
 	}
 
+	if isStructuredOutput() {
+		doc := projectChangeDocument{
+			SchemaVersion: schemaVersion,
+			Action:        "removed",
+			Name:          name,
+			Path:          project.GetConfigPath(projectDir),
+		}
+
+		switch {
+		case removedSkill:
+			doc.Type = string(registry.ItemTypeSkill)
+		case removedAgent:
+			doc.Type = string(registry.ItemTypeAgent)
+		case removedBundle:
+			doc.Type = string(registry.ItemTypeBundle)
+		}
+
+		return writeDocument(os.Stdout, doc)
+	}
+
 	mustWrite(os.Stdout, fmt.Sprintf("Removed %q from project\n", name))
 
 	return nil
@@ -489,7 +1246,7 @@ func runProjectRemove(_ *cobra.Command, args []string) error {
 
 func runProjectInstall(_ *cobra.Command, _ []string) error {
 	// Load project config
-	cfg, _, err := project.Load()
+	cfg, projectDir, err := project.Load()
 	if err != nil {
 		if errors.Is(err, project.ErrNotFound) {
 			return errNoProject
@@ -499,6 +1256,10 @@ func runProjectInstall(_ *cobra.Command, _ []string) error {
 	}
 
 	if cfg.IsEmpty() {
+		if isStructuredOutput() {
+			return writeDocument(os.Stdout, buildProjectResultsDocument(nil))
+		}
+
 		mustWrite(os.Stdout, "No skills or agents defined in project.\n")
 		mustWrite(os.Stdout, "Use 'skillsmith project add <name>' to add items.\n")
 
@@ -506,17 +1267,38 @@ func runProjectInstall(_ *cobra.Command, _ []string) error {
 	}
 
 	// Load manager
-	mgr, err := loader.NewManager()
+	mgr, err := newManager()
 	if err != nil {
-		return fmt.Errorf("initialize manager: %w", err)
+		return err
 	}
 
 	// Install all items
-	results := mgr.InstallProjectItems(cfg, config.ScopeLocal, projectInstallForce)
+	var results []loader.ProjectInstallResult
+
+	if projectInstallFrozen {
+		lock, lockErr := project.LoadLock(projectDir)
+		if lockErr != nil {
+			if errors.Is(lockErr, project.ErrLockNotFound) {
+				return errNoLockFile
+			}
+
+			return fmt.Errorf("load lock: %w", lockErr)
+		}
+
+		results = mgr.InstallProjectItemsFrozen(cfg, lock, config.ScopeLocal, projectInstallForce)
+	} else {
+		results = mgr.InstallProjectItems(cfg, config.ScopeLocal, projectInstallForce)
+	}
+
+	printWarnings(mgr)
 
 	// Display results
 	w := os.Stdout
 
+	if isStructuredOutput() {
+		return writeProjectInstallDocument(w, mgr, cfg, projectDir, results)
+	}
+
 	var installed, skipped, failed int
 
 	for _, r := range results {
@@ -544,6 +1326,52 @@ func runProjectInstall(_ *cobra.Command, _ []string) error {
 		return errInstallFailed
 	}
 
+	if !projectInstallFrozen {
+		err = writeProjectLock(mgr, cfg, projectDir)
+		if err != nil {
+			return err
+		}
+
+		mustWrite(w, fmt.Sprintf("Wrote %s\n", project.GetLockPath(projectDir)))
+	}
+
+	return nil
+}
+
+// writeProjectInstallDocument writes structured install results and updates the lock file on success.
+func writeProjectInstallDocument(
+	w io.Writer, mgr *loader.Manager, cfg *project.Config, projectDir string, results []loader.ProjectInstallResult,
+) error {
+	err := writeDocument(w, buildProjectResultsDocument(results))
+	if err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		if r.Error != nil {
+			return errInstallFailed
+		}
+	}
+
+	if projectInstallFrozen {
+		return nil
+	}
+
+	return writeProjectLock(mgr, cfg, projectDir)
+}
+
+// writeProjectLock records the currently resolved project items in the lock file.
+func writeProjectLock(mgr *loader.Manager, cfg *project.Config, projectDir string) error {
+	lock, err := mgr.LockProjectItems(cfg)
+	if err != nil {
+		return fmt.Errorf("lock project items: %w", err)
+	}
+
+	err = project.SaveLock(lock, projectDir)
+	if err != nil {
+		return fmt.Errorf("save lock: %w", err)
+	}
+
 	return nil
 }
 
@@ -558,20 +1386,33 @@ func runProjectStatus(_ *cobra.Command, _ []string) error {
 	}
 
 	if cfg.IsEmpty() {
+		if isStructuredOutput() {
+			return writeDocument(os.Stdout, buildProjectResultsDocument(nil))
+		}
+
 		mustWrite(os.Stdout, "No skills or agents defined in project.\n")
 
 		return nil
 	}
 
-	mgr, err := loader.NewManager()
+	mgr, err := newManager()
 	if err != nil {
-		return fmt.Errorf("initialize manager: %w", err)
+		return err
 	}
 
 	results := mgr.GetProjectStatus(cfg, config.ScopeLocal)
 
 	w := os.Stdout
 
+	if isStructuredOutput() {
+		err = writeDocument(w, buildProjectResultsDocument(results))
+		if err != nil {
+			return err
+		}
+
+		return checkProjectStatus(results)
+	}
+
 	mustWrite(w, "Project status:\n\n")
 
 	for _, r := range results {
@@ -587,9 +1428,13 @@ func runProjectStatus(_ *cobra.Command, _ []string) error {
 		} else {
 			mustWrite(w, fmt.Sprintf("  %s %s (%s): %s\n", status, r.ItemName, r.Tool, r.Reason))
 		}
+
+		if r.Installed != nil {
+			mustWrite(w, "      "+formatProvenance(r.Installed)+"\n")
+		}
 	}
 
-	return nil
+	return checkProjectStatus(results)
 }
 
 func runProjectList(_ *cobra.Command, _ []string) error {
@@ -604,6 +1449,10 @@ func runProjectList(_ *cobra.Command, _ []string) error {
 
 	w := os.Stdout
 
+	if isStructuredOutput() {
+		return writeDocument(w, buildProjectListDocument(cfg, projectDir))
+	}
+
 	mustWrite(w, fmt.Sprintf("Project: %s\n\n", project.GetConfigPath(projectDir)))
 
 	if len(cfg.Tools) > 0 {
@@ -632,6 +1481,16 @@ func runProjectList(_ *cobra.Command, _ []string) error {
 		mustWrite(w, "\n")
 	}
 
+	if len(cfg.Bundles) > 0 {
+		mustWrite(w, "Bundles:\n")
+
+		for _, b := range cfg.Bundles {
+			mustWrite(w, fmt.Sprintf("  - %s\n", b))
+		}
+
+		mustWrite(w, "\n")
+	}
+
 	if len(cfg.Registries) > 0 {
 		mustWrite(w, "Project registries:\n")
 
@@ -639,7 +1498,7 @@ func runProjectList(_ *cobra.Command, _ []string) error {
 			if r.IsLocal() {
 				mustWrite(w, fmt.Sprintf("  - %s: %s\n", r.Name, r.Path))
 			} else if r.IsGit() {
-				mustWrite(w, fmt.Sprintf("  - %s: %s\n", r.Name, r.URL))
+				mustWrite(w, fmt.Sprintf("  - %s: %s\n", r.Name, formatGitURL(r.URL, r.Ref)))
 			}
 		}
 	}
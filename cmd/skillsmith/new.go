@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+
+	"github.com/monke/skillsmith/internal/loader"
+	"github.com/monke/skillsmith/internal/registry"
+	"github.com/monke/skillsmith/internal/scaffold"
+)
+
+var errDescriptionMissing = errors.New("a description is required, pass --description")
+
+func runNew(cmd *cobra.Command, args []string) error {
+	itemType := registry.ItemType(args[0])
+	if itemType != registry.ItemTypeSkill && itemType != registry.ItemTypeAgent {
+		return fmt.Errorf("%w: %q, must be skill or agent", errUnknownItemType, args[0])
+	}
+
+	root, err := loader.LocalRegistryPath(newRegistry)
+	if err != nil {
+		return fmt.Errorf("find registry: %w", err)
+	}
+
+	opts := scaffold.Options{
+		Type:          itemType,
+		Name:          args[1],
+		Description:   newDescription,
+		Category:      newCategory,
+		Compatibility: toTools(newCompatibility),
+		Tags:          newTags,
+		Dir:           newDir,
+	}
+
+	// Check the name before prompting, so typos fail fast
+	err = registry.ValidateName(opts.Name)
+	if err != nil {
+		return err
+	}
+
+	// Prompts would end up in the document, so structured output takes flags only
+	if term.IsTerminal(os.Stdin.Fd()) && !isStructuredOutput() {
+		err = promptOptions(cmd, bufio.NewReader(os.Stdin), os.Stdout, &opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.Description == "" {
+		return errDescriptionMissing
+	}
+
+	if len(opts.Compatibility) == 0 {
+		opts.Compatibility = registry.BuiltinTools()
+	}
+
+	path, err := scaffold.Create(root, opts)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", itemType, err)
+	}
+
+	if isStructuredOutput() {
+		return writeDocument(os.Stdout, newDocument{
+			SchemaVersion: schemaVersion,
+			Type:          string(itemType),
+			Name:          opts.Name,
+			Path:          path,
+		})
+	}
+
+	mustWrite(os.Stdout, fmt.Sprintf("Created %s %q at %s\n", itemType, opts.Name, path))
+	mustWrite(os.Stdout, "Edit the TODOs, then run 'skillsmith lint "+root+"' to check it.\n")
+
+	return nil
+}
+
+// promptOptions asks for the values that weren't given as flags.
+func promptOptions(cmd *cobra.Command, r *bufio.Reader, w io.Writer, opts *scaffold.Options) error {
+	var err error
+
+	if !cmd.Flags().Changed("description") {
+		opts.Description, err = prompt(r, w, "Description")
+		if err != nil {
+			return err
+		}
+	}
+
+	if !cmd.Flags().Changed("category") {
+		opts.Category, err = prompt(r, w, "Category (optional)")
+		if err != nil {
+			return err
+		}
+	}
+
+	if !cmd.Flags().Changed("compatibility") {
+		answer, promptErr := prompt(r, w, fmt.Sprintf("Compatibility (comma-separated) [%s]",
+			strings.Join(toolNames(registry.BuiltinTools()), ", ")))
+		if promptErr != nil {
+			return promptErr
+		}
+
+		opts.Compatibility = toTools(splitList(answer))
+	}
+
+	if !cmd.Flags().Changed("tags") {
+		answer, promptErr := prompt(r, w, "Tags (comma-separated, optional)")
+		if promptErr != nil {
+			return promptErr
+		}
+
+		opts.Tags = splitList(answer)
+	}
+
+	return nil
+}
+
+// prompt writes a label and reads a single trimmed line.
+func prompt(r *bufio.Reader, w io.Writer, label string) (string, error) {
+	mustWrite(w, label+": ")
+
+	line, err := r.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read input: %w", err)
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+// splitList splits a comma-separated answer, dropping empty entries.
+func splitList(s string) []string {
+	var result []string
+
+	for part := range strings.SplitSeq(s, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			result = append(result, part)
+		}
+	}
+
+	return result
+}
+
+// toTools converts tool names to tools.
+func toTools(names []string) []registry.Tool {
+	tools := make([]registry.Tool, 0, len(names))
+
+	for _, name := range names {
+		tools = append(tools, registry.Tool(strings.ToLower(strings.TrimSpace(name))))
+	}
+
+	return tools
+}
@@ -0,0 +1,644 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/monke/skillsmith/internal/config"
+	"github.com/monke/skillsmith/internal/installer"
+	"github.com/monke/skillsmith/internal/lint"
+	"github.com/monke/skillsmith/internal/loader"
+	"github.com/monke/skillsmith/internal/project"
+	"github.com/monke/skillsmith/internal/registry"
+)
+
+// Output formats for the global --output flag.
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+// schemaVersion is the version of the structured output documents.
+// Bump it whenever a field is renamed or removed.
+const schemaVersion = 1
+
+var errUnknownOutputFormat = errors.New("unknown output format, must be one of text, json, yaml")
+
+// outputFormat is the value of the global --output flag.
+var outputFormat = outputText
+
+// validateOutputFormat checks the --output flag value.
+func validateOutputFormat(_ *cobra.Command, _ []string) error {
+	switch outputFormat {
+	case outputText, outputJSON, outputYAML:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", errUnknownOutputFormat, outputFormat)
+	}
+}
+
+// isStructuredOutput returns true if a machine-readable format was requested.
+func isStructuredOutput() bool {
+	return outputFormat == outputJSON || outputFormat == outputYAML
+}
+
+// writeDocument encodes a document in the requested structured format.
+func writeDocument(w io.Writer, doc any) error {
+	switch outputFormat {
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		err := enc.Encode(doc)
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+	case outputYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2) //nolint:mnd // conventional YAML indent
+
+		err := enc.Encode(doc)
+		if err != nil {
+			return fmt.Errorf("encode yaml: %w", err)
+		}
+
+		err = enc.Close()
+		if err != nil {
+			return fmt.Errorf("encode yaml: %w", err)
+		}
+	default:
+		return fmt.Errorf("%w: %q", errUnknownOutputFormat, outputFormat)
+	}
+
+	return nil
+}
+
+// listDocument is the structured output of 'skillsmith list'.
+type listDocument struct {
+	SchemaVersion int            `json:"schema_version" yaml:"schema_version"`
+	Items         []itemDocument `json:"items"          yaml:"items"`
+}
+
+// itemDocument describes a registry item and where it is installed.
+type itemDocument struct {
+	Name          string            `json:"name"          yaml:"name"`
+	Type          string            `json:"type"          yaml:"type"`
+	Source        string            `json:"source"        yaml:"source"`
+	Description   string            `json:"description"   yaml:"description"`
+	Category      string            `json:"category"      yaml:"category"`
+	Compatibility []string          `json:"compatibility" yaml:"compatibility"`
+	Overrides     []string          `json:"overrides"     yaml:"overrides"` // sources of shadowed definitions
+	Requires      []string          `json:"requires"      yaml:"requires"`
+	Installs      []installDocument `json:"installs"      yaml:"installs"`
+}
+
+// installDocument describes the installation state of an item for one tool and scope.
+type installDocument struct {
+	Tool  string `json:"tool"  yaml:"tool"`
+	Scope string `json:"scope" yaml:"scope"`
+	State string `json:"state" yaml:"state"`
+	Path  string `json:"path"  yaml:"path"`
+}
+
+// registryListDocument is the structured output of 'skillsmith registry list'.
+type registryListDocument struct {
+	SchemaVersion int                `json:"schema_version" yaml:"schema_version"`
+	Registries    []registryDocument `json:"registries"     yaml:"registries"`
+}
+
+// registryDocument describes a configured registry source.
+type registryDocument struct {
+	Name    string `json:"name"    yaml:"name"`
+	Type    string `json:"type"    yaml:"type"`
+	Path    string `json:"path"    yaml:"path"`
+	URL     string `json:"url"     yaml:"url"`
+	Ref     string `json:"ref"     yaml:"ref"`
+	Enabled bool   `json:"enabled" yaml:"enabled"`
+
+	// Load status; only set by 'registry list'.
+	Status      string  `json:"status,omitempty"       yaml:"status,omitempty"`
+	Error       string  `json:"error,omitempty"        yaml:"error,omitempty"`
+	ItemCount   int     `json:"item_count,omitempty"   yaml:"item_count,omitempty"`
+	LastUpdated string  `json:"last_updated,omitempty" yaml:"last_updated,omitempty"`
+	DurationMS  float64 `json:"duration_ms,omitempty"  yaml:"duration_ms,omitempty"` // load time, to the microsecond
+}
+
+// registryChangeDocument is the structured output of 'skillsmith registry add', 'add-git' and 'remove'.
+type registryChangeDocument struct {
+	SchemaVersion int              `json:"schema_version" yaml:"schema_version"`
+	Action        string           `json:"action"         yaml:"action"` // "added" or "removed"
+	Registry      registryDocument `json:"registry"       yaml:"registry"`
+}
+
+// registryUpdateDocument is the structured output of 'skillsmith registry update'.
+type registryUpdateDocument struct {
+	SchemaVersion int                    `json:"schema_version" yaml:"schema_version"`
+	Updates       []registryUpdateResult `json:"updates"        yaml:"updates"`
+}
+
+// registryUpdateResult describes the outcome of updating a single Git registry.
+type registryUpdateResult struct {
+	Name      string `json:"name"       yaml:"name"`
+	OldCommit string `json:"old_commit" yaml:"old_commit"`
+	NewCommit string `json:"new_commit" yaml:"new_commit"`
+	Changed   bool   `json:"changed"    yaml:"changed"`
+	Error     string `json:"error"      yaml:"error"`
+}
+
+// registryCleanDocument is the structured output of 'skillsmith registry clean'.
+type registryCleanDocument struct {
+	SchemaVersion int      `json:"schema_version" yaml:"schema_version"`
+	All           bool     `json:"all"            yaml:"all"`     // every cached repository was removed
+	Cleaned       []string `json:"cleaned"        yaml:"cleaned"` // names of the cleaned registries; empty if all is set
+}
+
+// diagnosticsDocument is the structured output of 'skillsmith registry diagnostics'.
+type diagnosticsDocument struct {
+	SchemaVersion int                 `json:"schema_version" yaml:"schema_version"`
+	Sources       []sourceDiagnostics `json:"sources"        yaml:"sources"`
+}
+
+// sourceDiagnostics lists the problems found while loading a single source.
+type sourceDiagnostics struct {
+	Name        string               `json:"name"        yaml:"name"`
+	Error       string               `json:"error"       yaml:"error"`
+	Diagnostics []diagnosticDocument `json:"diagnostics" yaml:"diagnostics"`
+}
+
+// diagnosticDocument describes a file that was skipped while loading.
+type diagnosticDocument struct {
+	Path   string `json:"path"   yaml:"path"`
+	Line   int    `json:"line"   yaml:"line"`
+	Reason string `json:"reason" yaml:"reason"`
+}
+
+// lintDocument is the structured output of 'skillsmith lint'.
+type lintDocument struct {
+	SchemaVersion int               `json:"schema_version" yaml:"schema_version"`
+	Path          string            `json:"path"           yaml:"path"`
+	Items         int               `json:"items"          yaml:"items"`
+	Errors        int               `json:"errors"         yaml:"errors"`
+	Warnings      int               `json:"warnings"       yaml:"warnings"`
+	Findings      []findingDocument `json:"findings"       yaml:"findings"`
+}
+
+// findingDocument describes a single lint finding.
+type findingDocument struct {
+	Path     string `json:"path"     yaml:"path"`
+	Line     int    `json:"line"     yaml:"line"`
+	Severity string `json:"severity" yaml:"severity"`
+	Message  string `json:"message"  yaml:"message"`
+}
+
+// projectListDocument is the structured output of 'skillsmith project list'.
+type projectListDocument struct {
+	SchemaVersion int                `json:"schema_version" yaml:"schema_version"`
+	Path          string             `json:"path"           yaml:"path"`
+	Tools         []string           `json:"tools"          yaml:"tools"`
+	Skills        []string           `json:"skills"         yaml:"skills"`
+	Agents        []string           `json:"agents"         yaml:"agents"`
+	Bundles       []string           `json:"bundles"        yaml:"bundles"`
+	Registries    []registryDocument `json:"registries"     yaml:"registries"`
+}
+
+// newDocument is the structured output of 'skillsmith new'.
+type newDocument struct {
+	SchemaVersion int    `json:"schema_version" yaml:"schema_version"`
+	Type          string `json:"type"           yaml:"type"`
+	Name          string `json:"name"           yaml:"name"`
+	Path          string `json:"path"           yaml:"path"` // the created file
+}
+
+// projectInitDocument is the structured output of 'skillsmith project init'.
+type projectInitDocument struct {
+	SchemaVersion int    `json:"schema_version" yaml:"schema_version"`
+	Path          string `json:"path"           yaml:"path"` // the created project config
+}
+
+// projectChangeDocument is the structured output of 'skillsmith project add' and 'project remove'.
+type projectChangeDocument struct {
+	SchemaVersion int    `json:"schema_version" yaml:"schema_version"`
+	Action        string `json:"action"         yaml:"action"` // "added", "replaced", "unchanged" or "removed"
+	Name          string `json:"name"           yaml:"name"`   // the reference as given
+	Type          string `json:"type"           yaml:"type"`
+	Replaced      string `json:"replaced"       yaml:"replaced"` // the reference it replaced, if any
+	Path          string `json:"path"           yaml:"path"`     // the project config
+}
+
+// projectResultsDocument is the structured output of 'skillsmith project status' and 'project install'.
+type projectResultsDocument struct {
+	SchemaVersion int                     `json:"schema_version" yaml:"schema_version"`
+	Results       []projectResultDocument `json:"results"        yaml:"results"`
+}
+
+// projectResultDocument describes the outcome for a single project item and tool.
+type projectResultDocument struct {
+	Name    string `json:"name"    yaml:"name"`
+	Type    string `json:"type"    yaml:"type"`
+	Tool    string `json:"tool"    yaml:"tool"`
+	State   string `json:"state"   yaml:"state"`
+	Success bool   `json:"success" yaml:"success"`
+	Skipped bool   `json:"skipped" yaml:"skipped"`
+	Path    string `json:"path"    yaml:"path"`
+	Reason  string `json:"reason"  yaml:"reason"`
+	Error   string `json:"error"   yaml:"error"`
+
+	// Installed is where an installed item came from, reported by 'project status'.
+	Installed *installedDocument `json:"installed" yaml:"installed"`
+}
+
+// installedDocument is the install record of an item. Source, commit and version are empty
+// for items installed before they were recorded.
+type installedDocument struct {
+	Source            string    `json:"source"             yaml:"source"`
+	Commit            string    `json:"commit"             yaml:"commit"`
+	Type              string    `json:"type"               yaml:"type"`
+	Tool              string    `json:"tool"               yaml:"tool"`
+	Path              string    `json:"path"               yaml:"path"`
+	SHA256            string    `json:"sha256"             yaml:"sha256"`
+	SkillsmithVersion string    `json:"skillsmith_version" yaml:"skillsmith_version"`
+	InstalledAt       time.Time `json:"installed_at"       yaml:"installed_at"`
+}
+
+// updateDocument is the structured output of 'skillsmith update'.
+type updateDocument struct {
+	SchemaVersion int                    `json:"schema_version" yaml:"schema_version"`
+	Results       []updateResultDocument `json:"results"        yaml:"results"`
+}
+
+// updateResultDocument describes the outcome of updating a single installed item for a tool.
+type updateResultDocument struct {
+	Name      string `json:"name"      yaml:"name"`
+	Type      string `json:"type"      yaml:"type"`
+	Tool      string `json:"tool"      yaml:"tool"`
+	State     string `json:"state"     yaml:"state"` // state before the update
+	Path      string `json:"path"      yaml:"path"`
+	Merged    bool   `json:"merged"    yaml:"merged"`
+	Conflicts int    `json:"conflicts" yaml:"conflicts"`
+	Skipped   bool   `json:"skipped"   yaml:"skipped"`
+	Error     string `json:"error"     yaml:"error"`
+}
+
+// diffDocument is the structured output of 'skillsmith diff'.
+type diffDocument struct {
+	SchemaVersion int                `json:"schema_version" yaml:"schema_version"`
+	Diffs         []toolDiffDocument `json:"diffs"          yaml:"diffs"`
+}
+
+// toolDiffDocument is the diff of an item for one tool. Diff is empty if there are no differences.
+type toolDiffDocument struct {
+	Tool string `json:"tool" yaml:"tool"`
+	Diff string `json:"diff" yaml:"diff"`
+}
+
+// historyDocument is the structured output of 'skillsmith history', newest backup first.
+type historyDocument struct {
+	SchemaVersion int              `json:"schema_version" yaml:"schema_version"`
+	Backups       []backupDocument `json:"backups"        yaml:"backups"`
+}
+
+// backupDocument describes a backup of an installed item for a tool.
+type backupDocument struct {
+	ID        string    `json:"id"         yaml:"id"`
+	Tool      string    `json:"tool"       yaml:"tool"`
+	Reason    string    `json:"reason"     yaml:"reason"` // what replaced or removed the backed up version
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+	Files     []string  `json:"files"      yaml:"files"`
+}
+
+// rollbackDocument is the structured output of 'skillsmith rollback'.
+type rollbackDocument struct {
+	SchemaVersion int                `json:"schema_version" yaml:"schema_version"`
+	Restored      []restoredDocument `json:"restored"       yaml:"restored"`
+}
+
+// restoredDocument describes the backup restored for a tool.
+type restoredDocument struct {
+	Tool   string         `json:"tool"   yaml:"tool"`
+	Path   string         `json:"path"   yaml:"path"`
+	Backup backupDocument `json:"backup" yaml:"backup"`
+}
+
+// buildListDocument collects every registry item with its state for all compatible tools and scopes.
+// If shadowedOnly is set, only items that override another source's definition are included.
+func buildListDocument(mgr *loader.Manager, shadowedOnly bool) listDocument {
+	// Index states by tool, scope and item name
+	type stateKey struct {
+		tool  registry.Tool
+		scope config.Scope
+		name  string
+	}
+
+	states := make(map[stateKey]installDocument)
+
+	for _, tool := range registry.AllTools() {
+		for _, scope := range config.AllScopes() {
+			for _, item := range mgr.ListItemsWithState(tool, scope, "") {
+				states[stateKey{tool, scope, item.Item.Name}] = installDocument{
+					Tool:  string(tool),
+					Scope: string(scope),
+					State: string(item.State),
+					Path:  item.InstallPath,
+				}
+			}
+		}
+	}
+
+	doc := listDocument{
+		SchemaVersion: schemaVersion,
+		Items:         make([]itemDocument, 0, len(mgr.Registry().Items)),
+	}
+
+	for _, item := range mgr.Registry().Items {
+		overrides := mgr.OverriddenSources(item.Name)
+		if shadowedOnly && len(overrides) == 0 {
+			continue
+		}
+
+		itemDoc := itemDocument{
+			Name:          item.Name,
+			Type:          string(item.Type),
+			Source:        item.Source,
+			Description:   item.Description,
+			Category:      item.Category,
+			Compatibility: toolNames(item.Compatibility),
+			Overrides:     overrides,
+			Requires:      append([]string{}, item.Requires...),
+			Installs:      []installDocument{},
+		}
+
+		for _, tool := range registry.AllTools() {
+			for _, scope := range config.AllScopes() {
+				if install, ok := states[stateKey{tool, scope, item.Name}]; ok {
+					itemDoc.Installs = append(itemDoc.Installs, install)
+				}
+			}
+		}
+
+		doc.Items = append(doc.Items, itemDoc)
+	}
+
+	return doc
+}
+
+// newRegistryDocument describes a configured registry, without its load status.
+func newRegistryDocument(reg loader.RegistryInfo) registryDocument {
+	return registryDocument{
+		Name:    reg.Name,
+		Type:    reg.Type,
+		Path:    reg.Path,
+		URL:     reg.URL,
+		Ref:     reg.Ref,
+		Enabled: reg.Enabled,
+	}
+}
+
+// buildRegistryListDocument converts registry infos to a document.
+func buildRegistryListDocument(registries []loader.RegistryInfo) registryListDocument {
+	doc := registryListDocument{
+		SchemaVersion: schemaVersion,
+		Registries:    make([]registryDocument, 0, len(registries)),
+	}
+
+	for _, reg := range registries {
+		regDoc := newRegistryDocument(reg)
+
+		switch {
+		case !reg.Enabled:
+			regDoc.Status = "disabled"
+		case reg.Status == nil:
+			regDoc.Status = "not_loaded"
+		case !reg.Status.OK():
+			regDoc.Status = "failed"
+			regDoc.Error = reg.Status.Error.Error()
+		default:
+			regDoc.Status = "ok"
+			regDoc.ItemCount = reg.Status.ItemCount
+		}
+
+		if reg.Status != nil {
+			regDoc.DurationMS = float64(reg.Status.Duration.Round(time.Microsecond)) / float64(time.Millisecond)
+		}
+
+		if reg.Status != nil && !reg.Status.LastUpdated.IsZero() {
+			regDoc.LastUpdated = reg.Status.LastUpdated.Format(time.RFC3339)
+		}
+
+		doc.Registries = append(doc.Registries, regDoc)
+	}
+
+	return doc
+}
+
+// buildRegistryUpdateDocument converts registry update results to a document.
+func buildRegistryUpdateDocument(updates []loader.RegistryUpdate) registryUpdateDocument {
+	doc := registryUpdateDocument{
+		SchemaVersion: schemaVersion,
+		Updates:       make([]registryUpdateResult, 0, len(updates)),
+	}
+
+	for _, update := range updates {
+		result := registryUpdateResult{
+			Name:      update.Name,
+			OldCommit: update.OldCommit,
+			NewCommit: update.NewCommit,
+			Changed:   update.Error == nil && update.OldCommit != update.NewCommit,
+		}
+
+		if update.Error != nil {
+			result.Error = update.Error.Error()
+		}
+
+		doc.Updates = append(doc.Updates, result)
+	}
+
+	return doc
+}
+
+// buildDiagnosticsDocument converts source statuses to a document.
+func buildDiagnosticsDocument(statuses []loader.SourceStatus) diagnosticsDocument {
+	doc := diagnosticsDocument{
+		SchemaVersion: schemaVersion,
+		Sources:       make([]sourceDiagnostics, 0, len(statuses)),
+	}
+
+	for _, status := range statuses {
+		src := sourceDiagnostics{
+			Name:        status.Name,
+			Diagnostics: make([]diagnosticDocument, 0, len(status.Diagnostics)),
+		}
+
+		if !status.OK() {
+			src.Error = status.Error.Error()
+		}
+
+		for _, diag := range status.Diagnostics {
+			src.Diagnostics = append(src.Diagnostics, diagnosticDocument{
+				Path:   diag.Path,
+				Line:   diag.Line,
+				Reason: diag.Reason,
+			})
+		}
+
+		doc.Sources = append(doc.Sources, src)
+	}
+
+	return doc
+}
+
+// buildLintDocument converts a lint report to a document.
+func buildLintDocument(path string, report *lint.Report) lintDocument {
+	doc := lintDocument{
+		SchemaVersion: schemaVersion,
+		Path:          path,
+		Items:         report.Items,
+		Errors:        report.Count(lint.SeverityError),
+		Warnings:      report.Count(lint.SeverityWarning),
+		Findings:      make([]findingDocument, 0, len(report.Findings)),
+	}
+
+	for _, finding := range report.Findings {
+		doc.Findings = append(doc.Findings, findingDocument{
+			Path:     finding.Path,
+			Line:     finding.Line,
+			Severity: string(finding.Severity),
+			Message:  finding.Message,
+		})
+	}
+
+	return doc
+}
+
+// buildProjectListDocument converts a project config to a document.
+func buildProjectListDocument(cfg *project.Config, projectDir string) projectListDocument {
+	doc := projectListDocument{
+		SchemaVersion: schemaVersion,
+		Path:          project.GetConfigPath(projectDir),
+		Tools:         append([]string{}, cfg.Tools...),
+		Skills:        append([]string{}, cfg.Skills...),
+		Agents:        append([]string{}, cfg.Agents...),
+		Bundles:       append([]string{}, cfg.Bundles...),
+		Registries:    make([]registryDocument, 0, len(cfg.Registries)),
+	}
+
+	for _, reg := range cfg.Registries {
+		regDoc := registryDocument{
+			Name:    reg.Name,
+			Type:    reg.Type,
+			Path:    reg.Path,
+			URL:     reg.URL,
+			Ref:     reg.Ref,
+			Enabled: reg.IsEnabled(),
+		}
+
+		switch {
+		case reg.IsLocal():
+			regDoc.Type = "local"
+		case reg.IsGit():
+			regDoc.Type = "git"
+		}
+
+		doc.Registries = append(doc.Registries, regDoc)
+	}
+
+	return doc
+}
+
+// buildProjectResultsDocument converts project install/status results to a document.
+func buildProjectResultsDocument(results []loader.ProjectInstallResult) projectResultsDocument {
+	doc := projectResultsDocument{
+		SchemaVersion: schemaVersion,
+		Results:       make([]projectResultDocument, 0, len(results)),
+	}
+
+	for _, r := range results {
+		resultDoc := projectResultDocument{
+			Name:    r.ItemName,
+			Type:    string(r.ItemType),
+			Tool:    string(r.Tool),
+			State:   string(r.State),
+			Success: r.Success,
+			Skipped: r.Skipped,
+			Path:    r.Path,
+			Reason:  r.Reason,
+		}
+
+		if r.Error != nil {
+			resultDoc.Error = r.Error.Error()
+		}
+
+		if r.Installed != nil {
+			resultDoc.Installed = &installedDocument{
+				Source:            r.Installed.Source,
+				Commit:            r.Installed.Commit,
+				Type:              string(r.Installed.Type),
+				Tool:              string(r.Installed.Tool),
+				Path:              r.Installed.Path,
+				SHA256:            r.Installed.Hash,
+				SkillsmithVersion: r.Installed.Version,
+				InstalledAt:       r.Installed.InstalledAt,
+			}
+		}
+
+		doc.Results = append(doc.Results, resultDoc)
+	}
+
+	return doc
+}
+
+// buildUpdateDocument converts update results to a document.
+func buildUpdateDocument(results []loader.UpdateResult) updateDocument {
+	doc := updateDocument{
+		SchemaVersion: schemaVersion,
+		Results:       make([]updateResultDocument, 0, len(results)),
+	}
+
+	for _, r := range results {
+		resultDoc := updateResultDocument{
+			Name:      r.ItemName,
+			Type:      string(r.ItemType),
+			Tool:      string(r.Tool),
+			State:     string(r.State),
+			Path:      r.Path,
+			Merged:    r.Merged,
+			Conflicts: r.Conflicts,
+			Skipped:   r.Skipped,
+		}
+
+		if r.Error != nil {
+			resultDoc.Error = r.Error.Error()
+		}
+
+		doc.Results = append(doc.Results, resultDoc)
+	}
+
+	return doc
+}
+
+// newBackupDocument converts a backup of an item for a tool to a document.
+func newBackupDocument(tool string, b installer.Backup) backupDocument {
+	return backupDocument{
+		ID:        b.ID,
+		Tool:      tool,
+		Reason:    b.Reason,
+		CreatedAt: b.CreatedAt,
+		Files:     append([]string{}, b.Files...),
+	}
+}
+
+// toolNames converts tools to their string names.
+func toolNames(tools []registry.Tool) []string {
+	names := make([]string, len(tools))
+	for i, t := range tools {
+		names[i] = string(t)
+	}
+
+	return names
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/monke/skillsmith/internal/adapter"
+	"github.com/monke/skillsmith/internal/config"
+	"github.com/monke/skillsmith/internal/loader"
+	"github.com/monke/skillsmith/internal/registry"
+)
+
+var (
+	errInvalidScope   = errors.New("invalid scope, must be local or global")
+	errMergeConflicts = errors.New("merged items have conflicts to resolve")
+	errItemsFailed    = errors.New("some items failed to update")
+)
+
+func runUpdate(_ *cobra.Command, args []string) error {
+	mgr, err := newManager()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range args {
+		_, err = mgr.GetItem(name)
+		if err != nil {
+			return fmt.Errorf("%w: %s", errItemNotFound, name)
+		}
+	}
+
+	tools, scope, err := parseTarget(updateTool, updateScope)
+	if err != nil {
+		return err
+	}
+
+	results := mgr.UpdateInstalled(args, tools, scope, updateMerge)
+
+	printWarnings(mgr)
+
+	var failed, conflicted int
+
+	for _, r := range results {
+		switch {
+		case r.Error != nil:
+			failed++
+		case r.Conflicts > 0:
+			conflicted++
+		}
+	}
+
+	w := os.Stdout
+
+	if isStructuredOutput() {
+		err = writeDocument(w, buildUpdateDocument(results))
+		if err != nil {
+			return err
+		}
+	} else {
+		writeUpdateOutput(results)
+	}
+
+	switch {
+	case failed > 0:
+		return fmt.Errorf("%w: %d", errItemsFailed, failed)
+	case conflicted > 0:
+		return fmt.Errorf("%w: %d", errMergeConflicts, conflicted)
+	default:
+		return nil
+	}
+}
+
+// writeUpdateOutput writes the results of 'skillsmith update' as text.
+func writeUpdateOutput(results []loader.UpdateResult) {
+	w := os.Stdout
+
+	if len(results) == 0 {
+		mustWrite(w, "All installed items are up to date.\n")
+
+		return
+	}
+
+	mustWrite(w, "Updating installed items:\n\n")
+
+	var updated, merged, skipped, failed int
+
+	for _, r := range results {
+		switch {
+		case r.Error != nil:
+			failed++
+
+			mustWrite(w, fmt.Sprintf("  [FAIL] %s (%s): %v\n", r.ItemName, r.Tool, r.Error))
+		case r.Skipped:
+			skipped++
+
+			mustWrite(w, fmt.Sprintf("  [SKIP] %s (%s): locally modified, use --merge to keep your changes\n",
+				r.ItemName, r.Tool))
+		case r.Conflicts > 0:
+			merged++
+
+			noun := "conflicts"
+			if r.Conflicts == 1 {
+				noun = "conflict"
+			}
+
+			mustWrite(w, fmt.Sprintf("  [CONF] %s (%s): %d %s to resolve in %s\n",
+				r.ItemName, r.Tool, r.Conflicts, noun, r.Path))
+		case r.Merged:
+			merged++
+
+			mustWrite(w, fmt.Sprintf("  [MRG]  %s (%s) -> %s\n", r.ItemName, r.Tool, r.Path))
+		default:
+			updated++
+
+			mustWrite(w, fmt.Sprintf("  [UPD]  %s (%s) -> %s\n", r.ItemName, r.Tool, r.Path))
+		}
+	}
+
+	mustWrite(w, fmt.Sprintf("\nUpdated: %d, Merged: %d, Skipped: %d, Failed: %d\n", updated, merged, skipped, failed))
+}
+
+// parseTarget resolves the --tool and --scope flags. An empty tool means all tools.
+// Tools declared in config.yaml are only known once the manager has been created.
+func parseTarget(tool, scope string) ([]registry.Tool, config.Scope, error) {
+	target := config.Scope(strings.ToLower(scope))
+	if target != config.ScopeLocal && target != config.ScopeGlobal {
+		return nil, "", fmt.Errorf("%w: %q", errInvalidScope, scope)
+	}
+
+	if tool == "" {
+		return registry.AllTools(), target, nil
+	}
+
+	t := registry.Tool(strings.ToLower(tool))
+
+	_, err := adapter.Get(t)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return []registry.Tool{t}, target, nil
+}
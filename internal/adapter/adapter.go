@@ -0,0 +1,161 @@
+// Package adapter defines how skillsmith installs items for each AI coding tool.
+// Builtin tools and tools declared in the user's config are both expressed as adapters.
+package adapter
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/monke/skillsmith/internal/config"
+	"github.com/monke/skillsmith/internal/registry"
+	"github.com/monke/skillsmith/internal/transformer"
+)
+
+// Adapter errors.
+var (
+	ErrUnknownTool           = errors.New("unknown tool")
+	ErrBuiltinTool           = errors.New("cannot redefine a builtin tool")
+	ErrInvalidToolDefinition = errors.New("invalid tool definition")
+)
+
+// Adapter describes how items are installed for a single AI coding tool.
+type Adapter interface {
+	// Tool returns the tool this adapter installs items for.
+	Tool() registry.Tool
+
+	// Paths returns the resolved scope directories, the layout for agents
+	// and skills, and the location of the metadata file.
+	Paths() (*config.Paths, error)
+
+	// Transform converts a registry item to the tool's file format.
+	Transform(item registry.Item) (string, error)
+}
+
+var (
+	mu       sync.RWMutex
+	adapters = builtinAdapters()
+	// Template variable values, see SetVars
+	globalVars  map[string]string
+	projectVars map[string]string
+)
+
+// Get returns the adapter for a tool.
+func Get(tool registry.Tool) (Adapter, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	a, ok := adapters[tool]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTool, tool)
+	}
+
+	return a, nil
+}
+
+// Register adds or replaces the adapter for a non-builtin tool
+// and makes the tool known to the registry.
+func Register(a Adapter, compatibleWith ...registry.Tool) error {
+	if isBuiltin(a.Tool()) {
+		return fmt.Errorf("%w: %s", ErrBuiltinTool, a.Tool())
+	}
+
+	mu.Lock()
+	adapters[a.Tool()] = a
+	mu.Unlock()
+
+	registry.RegisterTool(a.Tool(), compatibleWith...)
+
+	return nil
+}
+
+// RegisterDefinitions registers adapters for the tools declared in config.yaml.
+func RegisterDefinitions(defs []config.ToolDefinition) error {
+	for _, def := range defs {
+		a, err := NewDeclarative(def)
+		if err != nil {
+			return err
+		}
+
+		compat := make([]registry.Tool, len(def.CompatibleWith))
+		for i, t := range def.CompatibleWith {
+			compat[i] = registry.Tool(t)
+		}
+
+		err = Register(a, compat...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetPaths returns the resolved paths for a tool.
+func GetPaths(tool registry.Tool) (*config.Paths, error) {
+	a, err := Get(tool)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.Paths()
+}
+
+// SetVars sets the values of the template variables used to render item bodies:
+// global ones from config.yaml and project ones from the project config.
+// Project values override global ones, but only for local installs.
+func SetVars(global, project map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	globalVars = maps.Clone(global)
+	projectVars = maps.Clone(project)
+}
+
+// Render renders the body of an item with the variable values configured for a scope.
+// Items without vars are returned unchanged.
+func Render(item registry.Item, scope config.Scope) (registry.Item, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	values := globalVars
+
+	// Global installs are shared by all projects, so they don't use any project's values
+	if scope == config.ScopeLocal {
+		values = maps.Clone(globalVars)
+		if values == nil {
+			values = make(map[string]string)
+		}
+
+		maps.Copy(values, projectVars)
+	}
+
+	return transformer.Render(item, values)
+}
+
+// Transform renders an item's body for a scope and converts the item to the file format of a tool.
+func Transform(item registry.Item, tool registry.Tool, scope config.Scope) (string, error) {
+	a, err := Get(tool)
+	if err != nil {
+		return "", err
+	}
+
+	rendered, err := Render(item, scope)
+	if err != nil {
+		return "", err
+	}
+
+	return a.Transform(rendered)
+}
+
+// TransformUnrendered converts an item to the file format of a tool without rendering its body,
+// so the result is the same on every machine regardless of the configured variable values.
+func TransformUnrendered(item registry.Item, tool registry.Tool) (string, error) {
+	a, err := Get(tool)
+	if err != nil {
+		return "", err
+	}
+
+	return a.Transform(item)
+}
@@ -0,0 +1,78 @@
+package adapter
+
+import (
+	"slices"
+
+	"github.com/monke/skillsmith/internal/config"
+	"github.com/monke/skillsmith/internal/registry"
+	"github.com/monke/skillsmith/internal/transformer"
+)
+
+// builtinAdapters returns the adapters for the tools supported out of the box.
+func builtinAdapters() map[registry.Tool]Adapter {
+	builtins := []*fileAdapter{
+		{
+			tool:      registry.ToolOpenCode,
+			localDir:  ".opencode",
+			globalDir: ".config/opencode",
+			layout: config.Paths{
+				AgentsSubdir: "agents",
+				SkillsSubdir: "skills",
+			},
+			transform: plain(transformer.OpenCode),
+		},
+		{
+			tool:      registry.ToolClaude,
+			localDir:  ".claude",
+			globalDir: ".claude",
+			layout: config.Paths{
+				AgentsSubdir: "agents", // installed as subagents
+				SkillsSubdir: "skills",
+			},
+			transform: plain(transformer.Claude),
+		},
+		{
+			tool:      registry.ToolCursor,
+			localDir:  ".cursor",
+			globalDir: ".cursor",
+			layout: config.Paths{
+				AgentsSubdir:    "rules",
+				SkillsSubdir:    "rules",
+				SkillFileSuffix: ".mdc",
+				AgentFileSuffix: ".mdc",
+			},
+			transform: plain(transformer.Cursor),
+		},
+		{
+			tool:      registry.ToolCopilot,
+			localDir:  ".github",
+			globalDir: ".copilot",
+			layout: config.Paths{
+				AgentsSubdir:    "agents",
+				SkillsSubdir:    "instructions",
+				SkillFileSuffix: ".instructions.md",
+				AgentFileSuffix: ".agent.md",
+			},
+			transform: plain(transformer.Copilot),
+		},
+	}
+
+	adapters := make(map[registry.Tool]Adapter, len(builtins))
+	for _, a := range builtins {
+		adapters[a.tool] = a
+	}
+
+	return adapters
+}
+
+// isBuiltin returns true if the tool is supported out of the box.
+func isBuiltin(tool registry.Tool) bool {
+	return slices.Contains(registry.BuiltinTools(), tool)
+}
+
+// plain adapts a transform that cannot fail.
+func plain(fn func(registry.Item) string) func(registry.Item) (string, error) {
+	return func(item registry.Item) (string, error) {
+		return fn(item), nil
+	}
+}
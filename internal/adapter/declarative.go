@@ -0,0 +1,115 @@
+package adapter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/monke/skillsmith/internal/config"
+	"github.com/monke/skillsmith/internal/registry"
+)
+
+// defaultTemplate is used by declared tools that don't provide their own template.
+const defaultTemplate = `---
+name: {{ .Name }}
+description: {{ .Description }}
+---
+
+{{ .Body }}`
+
+// fileAdapter installs items as files below a local and a global directory.
+type fileAdapter struct {
+	tool      registry.Tool
+	localDir  string // relative to the working directory
+	globalDir string // relative to the home directory, or absolute
+	layout    config.Paths
+	transform func(registry.Item) (string, error)
+}
+
+// Tool returns the tool this adapter installs items for.
+func (a *fileAdapter) Tool() registry.Tool {
+	return a.tool
+}
+
+// Paths returns the resolved paths for the tool.
+func (a *fileAdapter) Paths() (*config.Paths, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("get home directory: %w", err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("get working directory: %w", err)
+	}
+
+	paths := a.layout
+	paths.LocalDir = filepath.Join(cwd, a.localDir)
+	paths.GlobalDir = a.globalDir
+
+	if !filepath.IsAbs(paths.GlobalDir) {
+		paths.GlobalDir = filepath.Join(homeDir, strings.TrimPrefix(a.globalDir, "~/"))
+	}
+
+	return &paths, nil
+}
+
+// Transform converts a registry item to the tool's file format.
+func (a *fileAdapter) Transform(item registry.Item) (string, error) {
+	return a.transform(item)
+}
+
+// NewDeclarative creates an adapter from a tool declared in config.yaml.
+// The item is rendered with the definition's Go text/template.
+func NewDeclarative(def config.ToolDefinition) (Adapter, error) {
+	if def.Name == "" || def.LocalDir == "" || def.GlobalDir == "" {
+		return nil, fmt.Errorf("%w: %q needs name, local_dir and global_dir", ErrInvalidToolDefinition, def.Name)
+	}
+
+	text := def.Template
+	if text == "" {
+		text = defaultTemplate
+	}
+
+	tmpl, err := template.New(def.Name).
+		Funcs(template.FuncMap{"join": strings.Join}).
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: parse template: %w", ErrInvalidToolDefinition, def.Name, err)
+	}
+
+	layout := config.Paths{
+		AgentsSubdir:    def.AgentsDir,
+		SkillsSubdir:    def.SkillsDir,
+		SkillFileSuffix: def.SkillSuffix,
+		AgentFileSuffix: def.AgentSuffix,
+		MetadataSubdir:  def.MetadataDir,
+	}
+
+	if layout.SkillsSubdir == "" {
+		layout.SkillsSubdir = "skills"
+	}
+
+	if layout.AgentsSubdir == "" {
+		layout.AgentsSubdir = "agents"
+	}
+
+	return &fileAdapter{
+		tool:      registry.Tool(def.Name),
+		localDir:  def.LocalDir,
+		globalDir: def.GlobalDir,
+		layout:    layout,
+		transform: func(item registry.Item) (string, error) {
+			var sb strings.Builder
+
+			err := tmpl.Execute(&sb, item)
+			if err != nil {
+				return "", fmt.Errorf("render %s template: %w", def.Name, err)
+			}
+
+			return sb.String(), nil
+		},
+	}, nil
+}
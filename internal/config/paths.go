@@ -35,46 +35,17 @@ type Paths struct {
 
 	// SkillsSubdir is the subdirectory for skills.
 	SkillsSubdir string
-}
 
-// GetPaths returns the paths for the specified tool.
-// The tool parameter is a string matching registry.Tool values.
-func GetPaths(tool string) (*Paths, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("get home directory: %w", err)
-	}
+	// SkillFileSuffix, if set, installs skills as flat files named <name><suffix>
+	// in SkillsSubdir instead of <name>/SKILL.md directories.
+	SkillFileSuffix string
 
-	cwd, err := os.Getwd()
-	if err != nil {
-		return nil, fmt.Errorf("get working directory: %w", err)
-	}
+	// AgentFileSuffix is the file suffix for agents. Defaults to ".md".
+	AgentFileSuffix string
 
-	switch tool {
-	case "opencode":
-		return &Paths{
-			LocalDir:     filepath.Join(cwd, ".opencode"),
-			GlobalDir:    filepath.Join(homeDir, ".config", "opencode"),
-			AgentsSubdir: "agents",
-			SkillsSubdir: "skills",
-		}, nil
-
-	case "claude":
-		return &Paths{
-			LocalDir:     filepath.Join(cwd, ".claude"),
-			GlobalDir:    filepath.Join(homeDir, ".claude"),
-			AgentsSubdir: "", // Claude Code doesn't have agents in the same way
-			SkillsSubdir: "skills",
-		}, nil
-
-	default:
-		return &Paths{
-			LocalDir:     cwd,
-			GlobalDir:    homeDir,
-			AgentsSubdir: "agents",
-			SkillsSubdir: "skills",
-		}, nil
-	}
+	// MetadataSubdir is the subdirectory holding the metadata file.
+	// Defaults to the scope directory itself.
+	MetadataSubdir string
 }
 
 // Exists checks if the target path already exists.
@@ -1,13 +1,20 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/monke/skillsmith/internal/fsutil"
 )
 
+// ErrInvalidFetchTTL is returned for a negative fetch_ttl.
+var ErrInvalidFetchTTL = errors.New("fetch_ttl must not be negative")
+
 // RegistrySource represents a configured registry source.
 type RegistrySource struct {
 	// Name is the unique identifier for this source.
@@ -22,6 +29,10 @@ type RegistrySource struct {
 	// URL is the Git repository URL (for git sources).
 	URL string `yaml:"url,omitempty"`
 
+	// Ref pins a Git source to a tag, branch or commit SHA.
+	// If empty, the repository's default branch is used.
+	Ref string `yaml:"ref,omitempty"`
+
 	// Enabled controls whether this source is active. Defaults to true.
 	Enabled *bool `yaml:"enabled,omitempty"`
 }
@@ -65,6 +76,38 @@ func (s *RegistrySource) IsGit() bool {
 type SkillsmithConfig struct {
 	// Registries is the list of configured registry sources.
 	Registries []RegistrySource `yaml:"registries"`
+
+	// Tools declares additional install targets beyond the builtin tools.
+	Tools []ToolDefinition `yaml:"tools,omitempty"`
+
+	// FetchTTL is how long a fetched Git registry is used without fetching again,
+	// as a Go duration (e.g. "15m", "1h"). "0" fetches on every load.
+	FetchTTL string `yaml:"fetch_ttl,omitempty"`
+
+	// Vars sets template variables for items that declare them.
+	// Values in a project's .skillsmith.yaml take precedence.
+	Vars map[string]string `yaml:"vars,omitempty"`
+}
+
+// DefaultFetchTTL is the fetch TTL used when fetch_ttl is not configured.
+const DefaultFetchTTL = 15 * time.Minute
+
+// GetFetchTTL returns the configured fetch TTL, or DefaultFetchTTL if unset.
+func (c *SkillsmithConfig) GetFetchTTL() (time.Duration, error) {
+	if c.FetchTTL == "" {
+		return DefaultFetchTTL, nil
+	}
+
+	ttl, err := time.ParseDuration(c.FetchTTL)
+	if err != nil {
+		return 0, fmt.Errorf("parse fetch_ttl: %w", err)
+	}
+
+	if ttl < 0 {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidFetchTTL, c.FetchTTL)
+	}
+
+	return ttl, nil
 }
 
 // DefaultConfig returns the default configuration with only the builtin registry.
@@ -129,7 +172,7 @@ func SaveConfig(cfg *SkillsmithConfig) error {
 		return fmt.Errorf("marshal config: %w", err)
 	}
 
-	err = os.WriteFile(path, data, filePermissions)
+	err = fsutil.WriteFile(path, data, filePermissions)
 	if err != nil {
 		return fmt.Errorf("write config: %w", err)
 	}
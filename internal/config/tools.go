@@ -0,0 +1,51 @@
+package config
+
+// ToolDefinition declares an additional install target in config.yaml,
+// so in-house agents can be targeted without changes to skillsmith.
+//
+// Example:
+//
+//	tools:
+//	  - name: acme
+//	    local_dir: .acme
+//	    global_dir: ~/.config/acme
+//	    compatible_with: [claude]
+//	    template: |
+//	      ---
+//	      name: {{ .Name }}
+//	      description: {{ .Description }}
+//	      ---
+//
+//	      {{ .Body }}
+type ToolDefinition struct {
+	// Name is the tool identifier used in compatibility lists and project configs.
+	Name string `yaml:"name"`
+
+	// LocalDir is the project-local directory, relative to the project root.
+	LocalDir string `yaml:"local_dir"`
+
+	// GlobalDir is the user-global directory, relative to the home directory or absolute.
+	GlobalDir string `yaml:"global_dir"`
+
+	// SkillsDir is the subdirectory for skills. Defaults to "skills".
+	SkillsDir string `yaml:"skills_dir,omitempty"`
+
+	// AgentsDir is the subdirectory for agents. Defaults to "agents".
+	AgentsDir string `yaml:"agents_dir,omitempty"`
+
+	// SkillSuffix, if set, installs skills as flat <name><suffix> files instead of <name>/SKILL.md.
+	SkillSuffix string `yaml:"skill_suffix,omitempty"`
+
+	// AgentSuffix is the file suffix for agents. Defaults to ".md".
+	AgentSuffix string `yaml:"agent_suffix,omitempty"`
+
+	// MetadataDir is the subdirectory holding skillsmith's metadata file.
+	MetadataDir string `yaml:"metadata_dir,omitempty"`
+
+	// CompatibleWith lists tools whose items can also be installed for this tool.
+	CompatibleWith []string `yaml:"compatible_with,omitempty"`
+
+	// Template is a Go text/template rendering an item to the tool's file format.
+	// The template receives the registry item. Defaults to name/description frontmatter plus body.
+	Template string `yaml:"template,omitempty"`
+}
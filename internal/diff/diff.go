@@ -0,0 +1,192 @@
+// Package diff compares and merges text line by line.
+package diff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Lines splits text into lines, keeping the line endings.
+// The last line has no newline if the text doesn't end with one.
+func Lines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// match is a pair of equal lines, by index in the two compared texts.
+type match struct {
+	a, b int
+}
+
+// lcs returns the longest common subsequence of a and b as matching line pairs, in order.
+// Files handled here are small, so the quadratic table is fine.
+func lcs(a, b []string) []match {
+	// lengths[i][j] is the LCS length of a[i:] and b[j:]
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	var matches []match
+
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			matches = append(matches, match{i, j})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	return matches
+}
+
+// Edit kinds of an edit script.
+const (
+	kindEqual  = ' '
+	kindDelete = '-'
+	kindInsert = '+'
+)
+
+// edit is one line of an edit script, with the indices of the lines before it in both texts.
+type edit struct {
+	kind byte
+	line string
+	a, b int
+}
+
+// editScript turns the longest common subsequence of a and b into an edit script from a to b.
+func editScript(a, b []string) []edit {
+	var edits []edit
+
+	i, j := 0, 0
+
+	for _, m := range append(lcs(a, b), match{len(a), len(b)}) {
+		for ; i < m.a; i++ {
+			edits = append(edits, edit{kindDelete, a[i], i, j})
+		}
+
+		for ; j < m.b; j++ {
+			edits = append(edits, edit{kindInsert, b[j], i, j})
+		}
+
+		if i < len(a) && j < len(b) {
+			edits = append(edits, edit{kindEqual, a[i], i, j})
+			i++
+			j++
+		}
+	}
+
+	return edits
+}
+
+// Unified returns a unified diff from a to b with the given number of context lines,
+// or an empty string if the texts are equal. fromName and toName label the two texts.
+func Unified(a, b, fromName, toName string, context int) string {
+	edits := editScript(Lines(a), Lines(b))
+
+	var sb strings.Builder
+
+	for start := 0; start < len(edits); {
+		// Find the next change
+		first := start
+		for first < len(edits) && edits[first].kind == kindEqual {
+			first++
+		}
+
+		if first == len(edits) {
+			break
+		}
+
+		// Extend the hunk while the next change is close enough to share context
+		end := first
+
+		for {
+			for end < len(edits) && edits[end].kind != kindEqual {
+				end++
+			}
+
+			next := end
+			for next < len(edits) && edits[next].kind == kindEqual {
+				next++
+			}
+
+			if next == len(edits) || next-end > 2*context {
+				end = min(end+context, len(edits))
+
+				break
+			}
+
+			end = next
+		}
+
+		if sb.Len() == 0 {
+			sb.WriteString("--- " + fromName + "\n")
+			sb.WriteString("+++ " + toName + "\n")
+		}
+
+		writeHunk(&sb, edits[max(first-context, start):end])
+
+		start = end
+	}
+
+	return sb.String()
+}
+
+// writeHunk writes a hunk header and its lines.
+func writeHunk(sb *strings.Builder, edits []edit) {
+	var countA, countB int
+
+	for _, e := range edits {
+		if e.kind != kindInsert {
+			countA++
+		}
+
+		if e.kind != kindDelete {
+			countB++
+		}
+	}
+
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(edits[0].a, countA), hunkRange(edits[0].b, countB))
+
+	for _, e := range edits {
+		sb.WriteByte(e.kind)
+		sb.WriteString(e.line)
+
+		if !strings.HasSuffix(e.line, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange formats the start line and line count of one side of a hunk.
+// Empty ranges start at the line before them, as in diff -u.
+func hunkRange(index, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", index)
+	case 1:
+		return strconv.Itoa(index + 1)
+	default:
+		return fmt.Sprintf("%d,%d", index+1, count)
+	}
+}
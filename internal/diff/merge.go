@@ -0,0 +1,108 @@
+package diff
+
+import (
+	"slices"
+	"strings"
+)
+
+// Conflict marker labels used by Merge3.
+const (
+	LocalLabel  = "local"
+	RemoteLabel = "registry"
+)
+
+// MergeResult is the outcome of a three-way merge.
+type MergeResult struct {
+	// Content is the merged text, with conflict markers around conflicting changes.
+	Content string
+
+	// Conflicts is the number of conflicting changes.
+	Conflicts int
+}
+
+// Merge3 merges the changes from base to local and from base to remote.
+// Changes to different parts of base are combined. Where both sides changed
+// the same lines differently, both versions are kept between conflict markers:
+//
+//	<<<<<<< local
+//	local lines
+//	=======
+//	remote lines
+//	>>>>>>> registry
+func Merge3(base, local, remote string) MergeResult {
+	o, a, b := Lines(base), Lines(local), Lines(remote)
+
+	// Base lines kept by both sides synchronize the three texts
+	inA := make(map[int]int)
+	for _, m := range lcs(o, a) {
+		inA[m.a] = m.b
+	}
+
+	inB := make(map[int]int)
+	for _, m := range lcs(o, b) {
+		inB[m.a] = m.b
+	}
+
+	var (
+		sb     strings.Builder
+		result MergeResult
+	)
+
+	i, j, k := 0, 0, 0
+
+	for line := range o {
+		ja, okA := inA[line]
+		kb, okB := inB[line]
+
+		if !okA || !okB {
+			continue
+		}
+
+		result.Conflicts += mergeChunk(&sb, o[i:line], a[j:ja], b[k:kb])
+		sb.WriteString(o[line])
+
+		i, j, k = line+1, ja+1, kb+1
+	}
+
+	result.Conflicts += mergeChunk(&sb, o[i:], a[j:], b[k:])
+	result.Content = sb.String()
+
+	return result
+}
+
+// mergeChunk writes the merge of a region that lies between synchronized lines.
+// Returns 1 if the region conflicts, 0 otherwise.
+func mergeChunk(sb *strings.Builder, base, local, remote []string) int {
+	switch {
+	case slices.Equal(local, base):
+		writeLines(sb, remote)
+	case slices.Equal(remote, base), slices.Equal(local, remote):
+		writeLines(sb, local)
+	default:
+		sb.WriteString("<<<<<<< " + LocalLabel + "\n")
+		writeBlock(sb, local)
+		sb.WriteString("=======\n")
+		writeBlock(sb, remote)
+		sb.WriteString(">>>>>>> " + RemoteLabel + "\n")
+
+		return 1
+	}
+
+	return 0
+}
+
+// writeLines writes lines as they are.
+func writeLines(sb *strings.Builder, lines []string) {
+	for _, line := range lines {
+		sb.WriteString(line)
+	}
+}
+
+// writeBlock writes lines inside conflict markers, ending the last line so the next marker starts on its own line.
+func writeBlock(sb *strings.Builder, lines []string) {
+	writeLines(sb, lines)
+
+	if len(lines) > 0 && !strings.HasSuffix(lines[len(lines)-1], "\n") {
+		sb.WriteString("\n")
+	}
+}
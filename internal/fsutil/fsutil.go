@@ -0,0 +1,47 @@
+// Package fsutil writes files so that readers never see them half written.
+package fsutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WriteFile writes data to a temporary file next to path and renames it over path,
+// so a crash or interrupt leaves either the old or the new content, never a truncated file.
+// The parent directory must exist.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+
+	// Clean up on any failure; after a successful rename the temp file is gone
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+
+	err = os.Chmod(tmp.Name(), perm)
+	if err != nil {
+		return fmt.Errorf("set permissions: %w", err)
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return fmt.Errorf("replace file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package fsutil
+
+import (
+	"fmt"
+	"os"
+)
+
+// lockPermissions is the permission of created lock files.
+const lockPermissions = 0o600
+
+// Lock is an exclusive advisory lock on a file, held until Unlock is called.
+// It guards a file against concurrent read-modify-write cycles from other processes.
+type Lock struct {
+	file *os.File
+}
+
+// LockFile takes an exclusive lock on the file at path, creating it if needed,
+// and waits until the lock is available. Lock a dedicated file rather than the
+// one being guarded, since WriteFile replaces that one. The parent directory must exist.
+func LockFile(path string) (*Lock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, lockPermissions) //nolint:gosec // path is constructed by callers
+	if err != nil {
+		return nil, fmt.Errorf("open lock file: %w", err)
+	}
+
+	err = lock(file)
+	if err != nil {
+		_ = file.Close()
+
+		return nil, fmt.Errorf("lock %s: %w", path, err)
+	}
+
+	return &Lock{file: file}, nil
+}
+
+// Unlock releases the lock. The lock file is left in place, since removing it
+// would let another process lock a file that a third one is about to open.
+func (l *Lock) Unlock() error {
+	err := unlock(l.file)
+
+	closeErr := l.file.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("unlock: %w", err)
+	}
+
+	return nil
+}
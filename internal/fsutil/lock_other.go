@@ -0,0 +1,15 @@
+//go:build !unix && !windows
+
+package fsutil
+
+import "os"
+
+// Platforms without file locking, such as js and wasip1, run a single process at a time.
+
+func lock(_ *os.File) error {
+	return nil
+}
+
+func unlock(_ *os.File) error {
+	return nil
+}
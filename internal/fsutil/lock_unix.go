@@ -0,0 +1,23 @@
+//go:build unix
+
+package fsutil
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func lock(file *os.File) error {
+	for {
+		err := unix.Flock(int(file.Fd()), unix.LOCK_EX) //nolint:gosec // file descriptors fit in an int
+		if !errors.Is(err, unix.EINTR) {
+			return err //nolint:wrapcheck // wrapped by LockFile
+		}
+	}
+}
+
+func unlock(file *os.File) error {
+	return unix.Flock(int(file.Fd()), unix.LOCK_UN) //nolint:gosec,wrapcheck // wrapped by Unlock
+}
@@ -0,0 +1,26 @@
+//go:build windows
+
+package fsutil
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockRange is the number of bytes locked. Locking past the end of the file is allowed,
+// so this covers the whole file regardless of its size.
+const lockRange = 1
+
+func lock(file *os.File) error {
+	ol := new(windows.Overlapped)
+
+	return windows.LockFileEx( //nolint:wrapcheck // wrapped by LockFile
+		windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, lockRange, 0, ol)
+}
+
+func unlock(file *os.File) error {
+	ol := new(windows.Overlapped)
+
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, lockRange, 0, ol) //nolint:wrapcheck // wrapped by Unlock
+}
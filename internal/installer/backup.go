@@ -0,0 +1,305 @@
+package installer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/monke/skillsmith/internal/config"
+	"github.com/monke/skillsmith/internal/fsutil"
+	"github.com/monke/skillsmith/internal/registry"
+)
+
+const (
+	// backupsDirName is the directory in the state dir that holds backups, one directory per item.
+	backupsDirName = "backups"
+
+	// backupFileName and backupBaseName are the installed file and base snapshot in a backup.
+	// Bundled files are kept in backupFilesDir.
+	backupFileName = "content"
+	backupBaseName = "base"
+	backupFilesDir = "files"
+
+	// backupIDFormat formats the creation time of a backup as its ID.
+	backupIDFormat = "20060102-150405.000000"
+
+	// maxBackups is the number of backups kept per item. Older ones are deleted.
+	maxBackups = 10
+)
+
+// Reasons a backup was taken.
+const (
+	BackupReasonInstall   = "install"
+	BackupReasonMerge     = "merge"
+	BackupReasonUninstall = "uninstall"
+	BackupReasonRollback  = "rollback"
+)
+
+// Backup errors.
+var (
+	ErrNoBackups      = errors.New("no backups")
+	ErrBackupNotFound = errors.New("backup not found")
+)
+
+// Backup records a previous version of an installed item, stashed before it was overwritten or removed.
+type Backup struct {
+	ID        string    `json:"id"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+	Files     []string  `json:"files,omitempty"` // bundled files, relative to the resource dir
+
+	// Installed is the install record at the time of the backup, nil if the file wasn't tracked.
+	Installed *InstalledItem `json:"installed,omitempty"`
+}
+
+// backupDir returns the directory of an item's backup.
+func backupDir(item registry.Item, tool registry.Tool, scope config.Scope, id string) (string, error) {
+	dir, err := GetStateDir(tool, scope)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, backupsDirName, item.Name, id), nil
+}
+
+// backup stashes the installed file of an item, its bundled files and base snapshot,
+// and records the backup in meta. Nothing is stashed if the item isn't installed.
+func backup(item registry.Item, tool registry.Tool, scope config.Scope, path string, meta *Metadata, reason string) error {
+	data, err := os.ReadFile(path) //nolint:gosec // path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("read installed file: %w", err)
+	}
+
+	now := time.Now()
+	b := Backup{ID: now.UTC().Format(backupIDFormat), Reason: reason, CreatedAt: now}
+
+	dir, err := backupDir(item, tool, scope, b.ID)
+	if err != nil {
+		return err
+	}
+
+	// Deleted again if the metadata recording it isn't saved
+	meta.createdBackups = append(meta.createdBackups, dir)
+
+	target := filepath.Join(dir, backupFileName)
+
+	err = config.EnsureDir(target)
+	if err != nil {
+		return err
+	}
+
+	err = fsutil.WriteFile(target, data, filePermissions)
+	if err != nil {
+		return fmt.Errorf("write backup: %w", err)
+	}
+
+	// Stash the bundled files that were installed, or those of the registry version if the item isn't tracked
+	fileList := filePaths(item.Files)
+
+	if installed, ok := meta.Get(item.Name); ok {
+		fileList = installed.Files
+		b.Installed = &installed
+	}
+
+	if len(fileList) > 0 {
+		resourceDir, dirErr := GetResourceDir(item, tool, scope)
+		if dirErr != nil {
+			return fmt.Errorf("get resource dir: %w", dirErr)
+		}
+
+		files := readInstalledFiles(resourceDir, fileList)
+
+		err = writeResources(filepath.Join(dir, backupFilesDir), files, nil)
+		if err != nil {
+			return fmt.Errorf("write backup files: %w", err)
+		}
+
+		b.Files = filePaths(files)
+	}
+
+	// Keep the base snapshot so merges still work after a rollback
+	snapshot, err := basePath(item, tool, scope)
+	if err != nil {
+		return err
+	}
+
+	base, err := os.ReadFile(snapshot) //nolint:gosec // path is constructed internally
+	if err == nil {
+		err = fsutil.WriteFile(filepath.Join(dir, backupBaseName), base, filePermissions)
+		if err != nil {
+			return fmt.Errorf("write backup base: %w", err)
+		}
+	}
+
+	// Keep the files of dropped backups until the metadata no longer lists them
+	for _, dropped := range meta.addBackup(item.Name, b) {
+		if droppedDir, dirErr := backupDir(item, tool, scope, dropped.ID); dirErr == nil {
+			meta.droppedBackups = append(meta.droppedBackups, droppedDir)
+		}
+	}
+
+	return nil
+}
+
+// History returns the backups of an item, oldest first.
+func History(item registry.Item, tool registry.Tool, scope config.Scope) ([]Backup, error) {
+	meta, err := LoadMetadata(tool, scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metadata: %w", err)
+	}
+
+	return meta.History(item.Name), nil
+}
+
+// Rollback restores a backup of an item, or the latest one if id is empty.
+// The version it replaces is backed up first, so a rollback can be rolled back too.
+// The restored backup is removed from the history.
+func Rollback(item registry.Item, tool registry.Tool, scope config.Scope, id string) (*Backup, error) {
+	// Check before locking, which would create the metadata directory of tools that have none
+	history, err := History(item, tool, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(history) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoBackups, item.Name)
+	}
+
+	var restored Backup
+
+	err = UpdateMetadata(tool, scope, func(meta *Metadata) error {
+		var restoreErr error
+
+		restored, restoreErr = restoreBackup(meta, item, tool, scope, id)
+
+		return restoreErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &restored, nil
+}
+
+// restoreBackup restores a backup of an item, or the latest one if id is empty,
+// and updates meta, which the caller saves.
+func restoreBackup(meta *Metadata, item registry.Item, tool registry.Tool, scope config.Scope, id string) (Backup, error) {
+	history := meta.History(item.Name)
+	if len(history) == 0 {
+		return Backup{}, fmt.Errorf("%w: %s", ErrNoBackups, item.Name)
+	}
+
+	restored := history[len(history)-1]
+
+	if id != "" {
+		found := false
+
+		for _, b := range history {
+			if b.ID == id {
+				restored = b
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return Backup{}, fmt.Errorf("%w: %s", ErrBackupNotFound, id)
+		}
+	}
+
+	path, err := GetInstallPath(item, tool, scope)
+	if err != nil {
+		return Backup{}, fmt.Errorf("failed to get install path: %w", err)
+	}
+
+	dir, err := backupDir(item, tool, scope, restored.ID)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, backupFileName)) //nolint:gosec // path is constructed internally
+	if err != nil {
+		return Backup{}, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	// Files that are installed now and have to make way for the backup's
+	current := filePaths(item.Files)
+	if installed, ok := meta.Get(item.Name); ok {
+		current = installed.Files
+	}
+
+	// Take the restored backup out first so pruning can't drop it.
+	// Its files are deleted once the metadata is saved.
+	meta.removeBackup(item.Name, restored.ID)
+	meta.droppedBackups = append(meta.droppedBackups, dir)
+
+	err = backup(item, tool, scope, path, meta, BackupReasonRollback)
+	if err != nil {
+		return Backup{}, fmt.Errorf("failed to back up current version: %w", err)
+	}
+
+	err = config.EnsureDir(path)
+	if err != nil {
+		return Backup{}, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	err = fsutil.WriteFile(path, data, filePermissions)
+	if err != nil {
+		return Backup{}, fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if len(restored.Files) > 0 || len(current) > 0 {
+		resourceDir, dirErr := GetResourceDir(item, tool, scope)
+		if dirErr != nil {
+			return Backup{}, fmt.Errorf("failed to get resource dir: %w", dirErr)
+		}
+
+		files := readInstalledFiles(filepath.Join(dir, backupFilesDir), restored.Files)
+
+		err = writeResources(resourceDir, files, current)
+		if err != nil {
+			return Backup{}, fmt.Errorf("failed to write bundled files: %w", err)
+		}
+	}
+
+	err = restoreBase(item, tool, scope, dir)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	// Restore the install record so the item's state is the one it had before
+	if restored.Installed != nil {
+		meta.Set(item.Name, *restored.Installed)
+	} else {
+		meta.Remove(item.Name)
+	}
+
+	return restored, nil
+}
+
+// restoreBase replaces an item's base snapshot with the one in a backup directory,
+// or removes it if the backup has none.
+func restoreBase(item registry.Item, tool registry.Tool, scope config.Scope, dir string) error {
+	base, err := os.ReadFile(filepath.Join(dir, backupBaseName)) //nolint:gosec // path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return removeBase(item, tool, scope)
+		}
+
+		return fmt.Errorf("failed to read backup base: %w", err)
+	}
+
+	err = saveBase(item, tool, scope, string(base))
+	if err != nil {
+		return fmt.Errorf("failed to save base snapshot: %w", err)
+	}
+
+	return nil
+}
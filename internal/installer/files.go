@@ -0,0 +1,166 @@
+package installer
+
+import (
+	"crypto/md5" //nolint:gosec // MD5 used for change detection, not security
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/monke/skillsmith/internal/config"
+	"github.com/monke/skillsmith/internal/fsutil"
+	"github.com/monke/skillsmith/internal/registry"
+)
+
+// GetResourceDir returns the directory where an item's bundled files are installed.
+// For directory layouts this is the skill directory itself; for flat layouts
+// (e.g. Cursor rules) it is a sibling directory named after the item.
+func GetResourceDir(item registry.Item, tool registry.Tool, scope config.Scope) (string, error) {
+	path, err := GetInstallPath(item, tool, scope)
+	if err != nil {
+		return "", err
+	}
+
+	if filepath.Base(path) == "SKILL.md" {
+		return filepath.Dir(path), nil
+	}
+
+	return filepath.Join(filepath.Dir(path), item.Name), nil
+}
+
+// ComputeTreeHash computes an MD5 hash of the main content together with bundled files.
+// Items without bundled files hash to the MD5 hash of the content alone.
+// It identifies content in lock files and in metadata written before schema version 1.
+func ComputeTreeHash(content string, files []registry.File) string {
+	return treeHash(md5.New(), content, files) //nolint:gosec // MD5 used for change detection, not security
+}
+
+// ComputeTreeSHA256 computes a SHA-256 hash of the main content together with bundled files.
+func ComputeTreeSHA256(content string, files []registry.File) string {
+	return treeHash(sha256.New(), content, files)
+}
+
+// treeHash hashes the main content followed by each bundled file's path and content, sorted by path.
+func treeHash(h hash.Hash, content string, files []registry.File) string {
+	sorted := slices.Clone(files)
+	slices.SortFunc(sorted, func(a, b registry.File) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	h.Write([]byte(content))
+
+	for _, f := range sorted {
+		h.Write([]byte("\x00" + f.Path + "\x00"))
+		h.Write(f.Content)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// filePaths returns the relative paths of bundled files.
+func filePaths(files []registry.File) []string {
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+	}
+
+	return paths
+}
+
+// readInstalledFiles reads bundled files from a resource directory.
+// Missing files are left out, so they show up as a hash mismatch.
+func readInstalledFiles(dir string, paths []string) []registry.File {
+	files := make([]registry.File, 0, len(paths))
+
+	for _, p := range paths {
+		path := filepath.Join(dir, filepath.FromSlash(p))
+
+		content, err := os.ReadFile(path) //nolint:gosec // path is from metadata
+		if err != nil {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		files = append(files, registry.File{Path: p, Content: content, Mode: info.Mode().Perm()})
+	}
+
+	return files
+}
+
+// writeResources writes bundled files to the resource directory and removes
+// files left over from a previous install that are no longer bundled.
+// Files that are executable in the registry are installed executable.
+func writeResources(dir string, files []registry.File, previous []string) error {
+	current := filePaths(files)
+
+	for _, p := range previous {
+		if !slices.Contains(current, p) {
+			err := os.Remove(filepath.Join(dir, filepath.FromSlash(p)))
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("remove stale file %s: %w", p, err)
+			}
+		}
+	}
+
+	for _, f := range files {
+		target := filepath.Join(dir, filepath.FromSlash(f.Path))
+
+		err := config.EnsureDir(target)
+		if err != nil {
+			return err
+		}
+
+		perm := os.FileMode(filePermissions)
+		if f.IsExecutable() {
+			perm = execPermissions
+		}
+
+		err = fsutil.WriteFile(target, f.Content, perm)
+		if err != nil {
+			return fmt.Errorf("write %s: %w", f.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// removeResources removes bundled files and prunes the directories they leave empty.
+func removeResources(dir string, paths []string) error {
+	for _, p := range paths {
+		err := os.Remove(filepath.Join(dir, filepath.FromSlash(p)))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove %s: %w", p, err)
+		}
+	}
+
+	pruneEmptyDirs(dir)
+
+	return nil
+}
+
+// pruneEmptyDirs removes dir and its subdirectories if they are empty.
+func pruneEmptyDirs(dir string) {
+	var dirs []string
+
+	_ = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			dirs = append(dirs, p)
+		}
+
+		return nil
+	})
+
+	// Deepest directories first; os.Remove fails on non-empty directories
+	for i := len(dirs) - 1; i >= 0; i-- {
+		_ = os.Remove(dirs[i])
+	}
+}
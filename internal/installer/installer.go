@@ -6,58 +6,90 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/monke/skillsmith/internal/adapter"
 	"github.com/monke/skillsmith/internal/config"
+	"github.com/monke/skillsmith/internal/fsutil"
 	"github.com/monke/skillsmith/internal/registry"
-	"github.com/monke/skillsmith/internal/transformer"
 )
 
 // filePermissions is the default permission for created files.
-const filePermissions = 0o600
+// Bundled files that are executable in the registry get execPermissions.
+const (
+	filePermissions = 0o600
+	execPermissions = 0o700
+)
 
 // Result represents the outcome of an installation.
 type Result struct {
 	Success bool
+
+	// Conflicts is the number of conflicts Merge left in the file.
+	Conflicts int
 }
 
 // GetInstallPath returns the full path where an item should be installed.
 func GetInstallPath(item registry.Item, tool registry.Tool, scope config.Scope) (string, error) {
-	paths, err := config.GetPaths(string(tool))
+	paths, err := adapter.GetPaths(tool)
 	if err != nil {
 		return "", fmt.Errorf("get paths: %w", err)
 	}
 
-	var baseDir string
-	if scope == config.ScopeGlobal {
-		baseDir = paths.GlobalDir
-	} else {
-		baseDir = paths.LocalDir
-	}
+	baseDir := scopeDir(paths, scope)
 
-	filename := item.Name + ".md"
+	agentSuffix := paths.AgentFileSuffix
+	if agentSuffix == "" {
+		agentSuffix = ".md"
+	}
 
 	switch item.Type {
 	case registry.ItemTypeAgent:
 		if paths.AgentsSubdir == "" {
-			// For tools without agent subdirs (like Claude), use skills instead
+			// For tools without agent subdirs, use skills instead
 			skillDir := filepath.Join(baseDir, paths.SkillsSubdir, item.Name)
 
 			return filepath.Join(skillDir, "SKILL.md"), nil
 		}
 
-		return filepath.Join(baseDir, paths.AgentsSubdir, filename), nil
+		return filepath.Join(baseDir, paths.AgentsSubdir, item.Name+agentSuffix), nil
 
 	case registry.ItemTypeSkill:
+		// Tools with flat skill files use skills/<name><suffix>
+		if paths.SkillFileSuffix != "" {
+			return filepath.Join(baseDir, paths.SkillsSubdir, item.Name+paths.SkillFileSuffix), nil
+		}
+
 		// Skills go in skills/<name>/SKILL.md
 		skillDir := filepath.Join(baseDir, paths.SkillsSubdir, item.Name)
 
 		return filepath.Join(skillDir, "SKILL.md"), nil
 
 	default:
-		return filepath.Join(baseDir, filename), nil
+		return filepath.Join(baseDir, item.Name+".md"), nil
+	}
+}
+
+// scopeDir returns the base directory of a tool for a scope.
+func scopeDir(paths *config.Paths, scope config.Scope) string {
+	if scope == config.ScopeGlobal {
+		return paths.GlobalDir
 	}
+
+	return paths.LocalDir
+}
+
+// ContentHash returns the hash of an item's content for a tool, as recorded in project lock files.
+// The body is hashed unrendered, so the hash doesn't depend on anyone's variable values.
+func ContentHash(item registry.Item, tool registry.Tool) (string, error) {
+	content, err := adapter.TransformUnrendered(item, tool)
+	if err != nil {
+		return "", fmt.Errorf("transform content: %w", err)
+	}
+
+	return ComputeTreeHash(content, item.Files), nil
 }
 
 // Install installs an item for a specific tool to the specified scope.
+// When force overwrites a different version, that version is backed up first.
 func Install(item registry.Item, tool registry.Tool, scope config.Scope, force bool) (*Result, error) {
 	// Check compatibility
 	if !item.IsCompatibleWith(tool) {
@@ -75,43 +107,96 @@ func Install(item registry.Item, tool registry.Tool, scope config.Scope, force b
 	}
 
 	// Transform content for the target tool
-	content, err := transformer.Transform(item, tool)
+	content, err := adapter.Transform(item, tool, scope)
 	if err != nil {
 		return nil, fmt.Errorf("failed to transform content: %w", err)
 	}
 
+	err = UpdateMetadata(tool, scope, func(meta *Metadata) error {
+		return writeItem(meta, item, tool, scope, path, content, content, BackupReasonInstall)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{Success: true}, nil
+}
+
+// writeItem writes an item's file and bundled files and records the install.
+// content is what the registry provides; fileContent is what goes into the file,
+// which differs from content when local changes were merged in.
+// A previously installed version that differs is backed up first, tagged with reason.
+// The install is recorded in meta, which the caller saves.
+func writeItem(
+	meta *Metadata, item registry.Item, tool registry.Tool, scope config.Scope, path, fileContent, content, reason string,
+) error {
+	previous, hasPrevious := meta.Get(item.Name)
+
+	if config.Exists(path) {
+		fileList := filePaths(item.Files)
+		if hasPrevious {
+			fileList = previous.Files
+		}
+
+		// Reinstalling the same content leaves nothing worth keeping
+		current, hashErr := computeInstalledHash(item, tool, scope, path, fileList, ComputeTreeSHA256)
+		if hashErr != nil || current != ComputeTreeSHA256(fileContent, item.Files) {
+			err := backup(item, tool, scope, path, meta, reason)
+			if err != nil {
+				return fmt.Errorf("failed to back up previous version: %w", err)
+			}
+		}
+	}
+
 	// Ensure parent directory exists
-	err = config.EnsureDir(path)
+	err := config.EnsureDir(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create directory: %w", err)
+		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
 	// Write the content
-	err = os.WriteFile(path, []byte(content), filePermissions)
+	err = fsutil.WriteFile(path, []byte(fileContent), filePermissions)
 	if err != nil {
-		return nil, fmt.Errorf("failed to write file: %w", err)
+		return fmt.Errorf("failed to write file: %w", err)
 	}
 
-	// Save hash to metadata
-	meta, err := LoadMetadata(tool, scope)
+	// Write bundled files, replacing those of a previous install
+	if len(item.Files) > 0 || len(previous.Files) > 0 {
+		resourceDir, dirErr := GetResourceDir(item, tool, scope)
+		if dirErr != nil {
+			return fmt.Errorf("failed to get resource dir: %w", dirErr)
+		}
+
+		err = writeResources(resourceDir, item.Files, previous.Files)
+		if err != nil {
+			return fmt.Errorf("failed to write bundled files: %w", err)
+		}
+	}
+
+	// Keep the registry content as the base of future merges
+	err = saveBase(item, tool, scope, content)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load metadata: %w", err)
+		return fmt.Errorf("failed to save base snapshot: %w", err)
 	}
 
+	// Record the registry content's hash and where it came from
 	meta.Set(item.Name, InstalledItem{
-		Hash:        ComputeHash(content),
+		Hash:        ComputeTreeSHA256(content, item.Files),
+		Files:       filePaths(item.Files),
 		InstalledAt: time.Now(),
+		Type:        item.Type,
+		Tool:        tool,
+		Path:        path,
+		Source:      item.Source,
+		Commit:      item.Commit,
+		Version:     Version,
 	})
 
-	err = SaveMetadata(tool, scope, meta)
-	if err != nil {
-		return nil, fmt.Errorf("failed to save metadata: %w", err)
-	}
-
-	return &Result{Success: true}, nil
+	return nil
 }
 
 // Uninstall removes an installed item for a specific tool.
+// The removed version is backed up and can be restored with Rollback.
 func Uninstall(item registry.Item, tool registry.Tool, scope config.Scope) (*Result, error) {
 	path, err := GetInstallPath(item, tool, scope)
 	if err != nil {
@@ -122,19 +207,52 @@ func Uninstall(item registry.Item, tool registry.Tool, scope config.Scope) (*Res
 		return &Result{Success: false}, nil
 	}
 
+	err = UpdateMetadata(tool, scope, func(meta *Metadata) error {
+		return removeItem(meta, item, tool, scope, path)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{Success: true}, nil
+}
+
+// removeItem backs up and removes an installed item and its bundled files,
+// and drops its install record from meta, which the caller saves.
+func removeItem(meta *Metadata, item registry.Item, tool registry.Tool, scope config.Scope, path string) error {
+	err := backup(item, tool, scope, path, meta, BackupReasonUninstall)
+	if err != nil {
+		return fmt.Errorf("failed to back up installed version: %w", err)
+	}
+
 	err = os.Remove(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to remove file: %w", err)
+		return fmt.Errorf("failed to remove file: %w", err)
 	}
 
-	// Remove from metadata (best effort, file is already removed)
-	meta, _ := LoadMetadata(tool, scope)
-	if meta != nil {
-		meta.Remove(item.Name)
-		_ = SaveMetadata(tool, scope, meta)
+	// Remove bundled files, both installed and currently in the registry
+	files := filePaths(item.Files)
+
+	if installedInfo, ok := meta.Get(item.Name); ok {
+		files = append(files, installedInfo.Files...)
 	}
 
-	return &Result{Success: true}, nil
+	resourceDir, err := GetResourceDir(item, tool, scope)
+	if err != nil {
+		return fmt.Errorf("failed to get resource dir: %w", err)
+	}
+
+	err = removeResources(resourceDir, files)
+	if err != nil {
+		return fmt.Errorf("failed to remove bundled files: %w", err)
+	}
+
+	_ = removeBase(item, tool, scope)
+
+	// Remove from metadata, keeping the backup for a rollback
+	meta.Remove(item.Name)
+
+	return nil
 }
 
 // GetItemState determines the installation state of an item.
@@ -160,19 +278,28 @@ func GetItemState(item registry.Item, tool registry.Tool, scope config.Scope) (I
 	// Get the stored hash for this item
 	installedInfo, hasMetadata := meta.Get(item.Name)
 
-	// Compute current file hash
-	fileHash, hashErr := ComputeFileHash(path)
+	// Compute current hash of the installed file and its bundled files.
+	// Use the file list recorded at install time so registry changes don't look like local edits.
+	fileList := filePaths(item.Files)
+	if hasMetadata {
+		fileList = installedInfo.Files
+	}
+
+	// Hash with the algorithm of the recorded hash, which is MD5 for some migrated items
+	installedHash, hashTree := installedInfo.recordedHash()
+
+	fileHash, hashErr := computeInstalledHash(item, tool, scope, path, fileList, hashTree)
 	if hashErr != nil {
 		return StateModified, path, nil //nolint:nilerr // intentional: treat as modified
 	}
 
 	// Compute what the registry version would look like
-	registryContent, transformErr := transformer.Transform(item, tool)
+	registryContent, transformErr := adapter.Transform(item, tool, scope)
 	if transformErr != nil {
 		return StateModified, path, nil //nolint:nilerr // intentional: treat as modified
 	}
 
-	registryHash := ComputeHash(registryContent)
+	registryHash := hashTree(registryContent, item.Files)
 
 	// If no metadata, we don't know the original installed version
 	// Compare file to registry to make best guess
@@ -186,7 +313,6 @@ func GetItemState(item registry.Item, tool registry.Tool, scope config.Scope) (I
 	}
 
 	// We have metadata - compare all three hashes
-	installedHash := installedInfo.Hash
 	fileMatchesInstalled := fileHash == installedHash
 	registryMatchesInstalled := registryHash == installedHash
 
@@ -205,3 +331,130 @@ func GetItemState(item registry.Item, tool registry.Tool, scope config.Scope) (I
 		return StateModifiedWithUpdate, path, nil
 	}
 }
+
+// computeInstalledHash hashes an installed file together with the given bundled files using hashTree.
+func computeInstalledHash(
+	item registry.Item, tool registry.Tool, scope config.Scope, path string, files []string,
+	hashTree func(string, []registry.File) string,
+) (string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is from trusted source
+	if err != nil {
+		return "", fmt.Errorf("read file: %w", err)
+	}
+
+	if len(files) == 0 {
+		return hashTree(string(data), nil), nil
+	}
+
+	resourceDir, err := GetResourceDir(item, tool, scope)
+	if err != nil {
+		return "", err
+	}
+
+	return hashTree(string(data), readInstalledFiles(resourceDir, files)), nil
+}
+
+// MigrateLegacyAgent moves a Claude agent that older versions installed as a skill
+// (skills/<name>/SKILL.md) to its subagent location (agents/<name>.md).
+// Unmodified files are reinstalled in the subagent format; locally modified files
+// are moved as-is so no changes are lost. Only files that the metadata shows skillsmith
+// installed as this agent are migrated, never a user's own skill of the same name.
+// Returns true if a file was migrated.
+func MigrateLegacyAgent(item registry.Item, tool registry.Tool, scope config.Scope) (bool, error) {
+	if item.Type != registry.ItemTypeAgent || tool != registry.ToolClaude {
+		return false, nil
+	}
+
+	paths, err := adapter.GetPaths(tool)
+	if err != nil {
+		return false, fmt.Errorf("get paths: %w", err)
+	}
+
+	legacyDir := filepath.Join(scopeDir(paths, scope), paths.SkillsSubdir, item.Name)
+	legacyPath := filepath.Join(legacyDir, "SKILL.md")
+
+	if !config.Exists(legacyPath) {
+		return false, nil
+	}
+
+	path, err := GetInstallPath(item, tool, scope)
+	if err != nil {
+		return false, fmt.Errorf("get install path: %w", err)
+	}
+
+	// Never overwrite an agent that is already installed in the new location
+	if config.Exists(path) {
+		return false, nil
+	}
+
+	meta, err := LoadMetadata(tool, scope)
+	if err != nil {
+		return false, fmt.Errorf("load metadata: %w", err)
+	}
+
+	installedInfo, hasMetadata := meta.Get(item.Name)
+	if !hasMetadata || (installedInfo.Type != "" && installedInfo.Type != registry.ItemTypeAgent) {
+		return false, nil
+	}
+
+	installedHash, hashTree := installedInfo.recordedHash()
+
+	legacyHash, err := computeInstalledHash(item, tool, scope, legacyPath, nil, hashTree)
+	if err != nil {
+		return false, err
+	}
+
+	if legacyHash == installedHash {
+		// Install first, so the agent isn't lost if that fails
+		result, installErr := Install(item, tool, scope, true)
+		if installErr != nil {
+			return false, installErr
+		}
+
+		if !result.Success {
+			return false, nil
+		}
+
+		err = os.Remove(legacyPath)
+		if err != nil {
+			return true, fmt.Errorf("remove legacy agent: %w", err)
+		}
+
+		_ = os.Remove(legacyDir) // only succeeds if empty
+
+		return true, nil
+	}
+
+	err = UpdateMetadata(tool, scope, func(meta *Metadata) error {
+		return moveLegacyAgent(meta, item.Name, legacyPath, path)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	_ = os.Remove(legacyDir) // only succeeds if empty
+
+	return true, nil
+}
+
+// moveLegacyAgent moves a locally modified legacy agent file as-is and points its
+// install record at the new location, so state, diff and merge find it there.
+func moveLegacyAgent(meta *Metadata, name, legacyPath, path string) error {
+	err := config.EnsureDir(path)
+	if err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	err = os.Rename(legacyPath, path)
+	if err != nil {
+		return fmt.Errorf("move legacy agent: %w", err)
+	}
+
+	if installed, ok := meta.Get(name); ok {
+		installed.Type = registry.ItemTypeAgent
+		installed.Path = path
+		meta.Set(name, installed)
+	}
+
+	return nil
+}
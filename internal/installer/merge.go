@@ -0,0 +1,151 @@
+package installer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/monke/skillsmith/internal/adapter"
+	"github.com/monke/skillsmith/internal/config"
+	"github.com/monke/skillsmith/internal/diff"
+	"github.com/monke/skillsmith/internal/fsutil"
+	"github.com/monke/skillsmith/internal/registry"
+)
+
+const (
+	// stateDirName is the directory next to the metadata file that holds install state.
+	stateDirName = ".skillsmith"
+
+	// diffContext is the number of unchanged lines shown around changes by Diff.
+	diffContext = 3
+)
+
+// Merge and diff errors.
+var (
+	ErrNoBase       = errors.New("no base snapshot to merge from")
+	ErrNotInstalled = errors.New("item is not installed")
+)
+
+// GetStateDir returns the directory holding install state for a tool and scope,
+// such as the base snapshots used for merging.
+func GetStateDir(tool registry.Tool, scope config.Scope) (string, error) {
+	paths, err := adapter.GetPaths(tool)
+	if err != nil {
+		return "", fmt.Errorf("get paths: %w", err)
+	}
+
+	return filepath.Join(scopeDir(paths, scope), paths.MetadataSubdir, stateDirName), nil
+}
+
+// basePath returns the path of an item's base snapshot: the content it was last installed with.
+func basePath(item registry.Item, tool registry.Tool, scope config.Scope) (string, error) {
+	dir, err := GetStateDir(tool, scope)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "base", item.Name), nil
+}
+
+// saveBase records the content an item was installed with.
+func saveBase(item registry.Item, tool registry.Tool, scope config.Scope, content string) error {
+	path, err := basePath(item, tool, scope)
+	if err != nil {
+		return err
+	}
+
+	err = config.EnsureDir(path)
+	if err != nil {
+		return err
+	}
+
+	return fsutil.WriteFile(path, []byte(content), filePermissions)
+}
+
+// removeBase removes an item's base snapshot, if any.
+func removeBase(item registry.Item, tool registry.Tool, scope config.Scope) error {
+	path, err := basePath(item, tool, scope)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove base snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// Merge updates an installed item to the registry version while keeping local changes,
+// using a three-way merge of the base snapshot, the installed file and the registry content.
+// Conflicting changes are written with conflict markers and counted in the result.
+// Bundled files are replaced with the registry versions.
+func Merge(item registry.Item, tool registry.Tool, scope config.Scope) (*Result, error) {
+	path, err := GetInstallPath(item, tool, scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get install path: %w", err)
+	}
+
+	local, err := os.ReadFile(path) //nolint:gosec // path is constructed internally
+	if err != nil {
+		return nil, fmt.Errorf("failed to read installed file: %w", err)
+	}
+
+	snapshot, err := basePath(item, tool, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := os.ReadFile(snapshot) //nolint:gosec // path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s was installed by an older version, reinstall it to merge later updates",
+				ErrNoBase, item.Name)
+		}
+
+		return nil, fmt.Errorf("failed to read base snapshot: %w", err)
+	}
+
+	content, err := adapter.Transform(item, tool, scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transform content: %w", err)
+	}
+
+	merged := diff.Merge3(string(base), string(local), content)
+
+	err = UpdateMetadata(tool, scope, func(meta *Metadata) error {
+		return writeItem(meta, item, tool, scope, path, merged.Content, content, BackupReasonMerge)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{Success: true, Conflicts: merged.Conflicts}, nil
+}
+
+// Diff returns a unified diff from the installed file of an item to the content the registry
+// would install now, or an empty string if they are the same. Bundled files are not compared.
+func Diff(item registry.Item, tool registry.Tool, scope config.Scope) (string, error) {
+	path, err := GetInstallPath(item, tool, scope)
+	if err != nil {
+		return "", fmt.Errorf("failed to get install path: %w", err)
+	}
+
+	installed, err := os.ReadFile(path) //nolint:gosec // path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%w: %s", ErrNotInstalled, path)
+		}
+
+		return "", fmt.Errorf("failed to read installed file: %w", err)
+	}
+
+	content, err := adapter.Transform(item, tool, scope)
+	if err != nil {
+		return "", fmt.Errorf("failed to transform content: %w", err)
+	}
+
+	return diff.Unified(string(installed), content, path, item.Source+"/"+item.Name, diffContext), nil
+}
@@ -1,19 +1,30 @@
 package installer
 
 import (
-	"crypto/md5" //nolint:gosec // MD5 used for change detection, not security
-	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
+	"github.com/monke/skillsmith/internal/adapter"
 	"github.com/monke/skillsmith/internal/config"
+	"github.com/monke/skillsmith/internal/fsutil"
 	"github.com/monke/skillsmith/internal/registry"
 )
 
-const metadataFilename = ".skillsmith.json"
+const (
+	metadataFilename = ".skillsmith.json"
+
+	// metadataSchemaVersion is the version of the metadata file format.
+	// Older files are migrated when loaded, see migrateMetadata.
+	metadataSchemaVersion = 1
+
+	// metadataLockName is the lock file in the state dir that serializes metadata updates.
+	metadataLockName = "metadata.lock"
+)
 
 // ItemState represents the installation state of an item.
 type ItemState string
@@ -66,21 +77,54 @@ func (s ItemState) IsModified() bool {
 	return s == StateModified || s == StateModifiedWithUpdate
 }
 
-// InstalledItem tracks metadata for an installed item.
+// InstalledItem tracks metadata for an installed item: what was installed, where, and where it came from.
 type InstalledItem struct {
-	Hash        string    `json:"hash"`
+	// Hash is the SHA-256 hash of the installed content and bundled files.
+	Hash string `json:"sha256,omitempty"`
+
+	// LegacyHash is the MD5 hash recorded before schema version 1. It is only kept for items
+	// whose files had changed when their metadata was migrated, so their SHA-256 hash is unknown.
+	LegacyHash string `json:"md5,omitempty"`
+
+	Files       []string  `json:"files,omitempty"` // bundled files, relative to the resource dir
 	InstalledAt time.Time `json:"installed_at"`
+
+	Type    registry.ItemType `json:"type,omitempty"`
+	Tool    registry.Tool     `json:"tool,omitempty"`
+	Path    string            `json:"path,omitempty"`
+	Source  string            `json:"source,omitempty"` // registry the item was installed from
+	Commit  string            `json:"commit,omitempty"` // commit of a Git registry
+	Version string            `json:"skillsmith_version,omitempty"`
+}
+
+// recordedHash returns the hash recorded at install time and the function that computes it:
+// SHA-256, or MD5 for items migrated from before schema version 1 without a SHA-256 hash.
+func (i InstalledItem) recordedHash() (string, func(string, []registry.File) string) {
+	if i.Hash == "" && i.LegacyHash != "" {
+		return i.LegacyHash, ComputeTreeHash
+	}
+
+	return i.Hash, ComputeTreeSHA256
 }
 
 // Metadata stores installation state for all items.
 type Metadata struct {
-	Installed map[string]InstalledItem `json:"installed"`
+	SchemaVersion int                      `json:"schema_version"`
+	Installed     map[string]InstalledItem `json:"installed"`
+	Backups       map[string][]Backup      `json:"backups,omitempty"` // oldest first
+
+	// Backup directories created since the metadata was loaded, and those of backups dropped
+	// from it. UpdateMetadata deletes the dropped ones once the metadata is saved,
+	// and the created ones if it isn't.
+	createdBackups []string
+	droppedBackups []string
 }
 
 // NewMetadata creates an empty metadata struct.
 func NewMetadata() *Metadata {
 	return &Metadata{
-		Installed: make(map[string]InstalledItem),
+		SchemaVersion: metadataSchemaVersion,
+		Installed:     make(map[string]InstalledItem),
 	}
 }
 
@@ -105,25 +149,85 @@ func (m *Metadata) Remove(itemName string) {
 	delete(m.Installed, itemName)
 }
 
+// History returns the backups of an item, oldest first.
+func (m *Metadata) History(itemName string) []Backup {
+	return m.Backups[itemName]
+}
+
+// addBackup records a backup of an item and returns the backups dropped to stay within maxBackups.
+func (m *Metadata) addBackup(itemName string, b Backup) []Backup {
+	if m.Backups == nil {
+		m.Backups = make(map[string][]Backup)
+	}
+
+	m.Backups[itemName] = append(m.Backups[itemName], b)
+	history := m.Backups[itemName]
+
+	var dropped []Backup
+	if len(history) > maxBackups {
+		dropped = history[:len(history)-maxBackups]
+		history = history[len(history)-maxBackups:]
+	}
+
+	m.Backups[itemName] = history
+
+	return dropped
+}
+
+// discardCreatedBackups deletes the directories of the backups taken since the metadata was loaded.
+func (m *Metadata) discardCreatedBackups() {
+	for _, dir := range m.createdBackups {
+		_ = os.RemoveAll(dir)
+	}
+
+	m.createdBackups = nil
+}
+
+// deleteDroppedBackups deletes the directories of the backups dropped from the metadata.
+func (m *Metadata) deleteDroppedBackups() {
+	for _, dir := range m.droppedBackups {
+		_ = os.RemoveAll(dir)
+	}
+
+	m.droppedBackups = nil
+}
+
+// removeBackup deletes a backup of an item from the history.
+func (m *Metadata) removeBackup(itemName, id string) {
+	history := slices.DeleteFunc(slices.Clone(m.Backups[itemName]), func(b Backup) bool {
+		return b.ID == id
+	})
+
+	if len(history) == 0 {
+		delete(m.Backups, itemName)
+
+		return
+	}
+
+	m.Backups[itemName] = history
+}
+
 // GetMetadataPath returns the path to the metadata file for a tool and scope.
 func GetMetadataPath(tool registry.Tool, scope config.Scope) (string, error) {
-	paths, err := config.GetPaths(string(tool))
+	paths, err := adapter.GetPaths(tool)
 	if err != nil {
 		return "", fmt.Errorf("get paths: %w", err)
 	}
 
-	var baseDir string
+	return filepath.Join(scopeDir(paths, scope), paths.MetadataSubdir, metadataFilename), nil
+}
 
-	if scope == config.ScopeGlobal {
-		baseDir = paths.GlobalDir
-	} else {
-		baseDir = paths.LocalDir
-	}
+// Metadata errors.
+var (
+	ErrCorruptMetadata     = errors.New("corrupt metadata file")
+	ErrUnsupportedMetadata = errors.New("metadata file is from a newer skillsmith, upgrade to use it")
+)
 
-	return filepath.Join(baseDir, metadataFilename), nil
-}
+// Version is the skillsmith version recorded with installed items. main sets it to the build version.
+var Version = "dev"
 
 // LoadMetadata loads metadata from disk, or returns empty metadata if file doesn't exist.
+// A file that can't be parsed is an error, so it isn't overwritten with empty metadata.
 func LoadMetadata(tool registry.Tool, scope config.Scope) (*Metadata, error) {
 	path, err := GetMetadataPath(tool, scope)
 	if err != nil {
@@ -143,8 +247,19 @@ func LoadMetadata(tool registry.Tool, scope config.Scope) (*Metadata, error) {
 
 	err = json.Unmarshal(data, &meta)
 	if err != nil {
-		// If metadata is corrupted, return empty metadata
-		return NewMetadata(), nil //nolint:nilerr // intentional: corrupted metadata is treated as empty
+		return nil, fmt.Errorf("%w %s, fix or delete it: %w", ErrCorruptMetadata, path, err)
+	}
+
+	if meta.SchemaVersion > metadataSchemaVersion {
+		return nil, fmt.Errorf("%w: %s has schema version %d", ErrUnsupportedMetadata, path, meta.SchemaVersion)
+	}
+
+	// Older files are migrated in memory and written in the current format on the next change
+	if meta.SchemaVersion < metadataSchemaVersion {
+		err = migrateMetadata(&meta, data, tool, scope)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s, fix or delete it: %w", ErrCorruptMetadata, path, err)
+		}
 	}
 
 	if meta.Installed == nil {
@@ -162,7 +277,7 @@ func SaveMetadata(tool registry.Tool, scope config.Scope, meta *Metadata) error
 	}
 
 	// If metadata is empty, remove the file instead of saving empty JSON
-	if len(meta.Installed) == 0 {
+	if len(meta.Installed) == 0 && len(meta.Backups) == 0 {
 		err = os.Remove(path)
 		if err != nil && !os.IsNotExist(err) {
 			return fmt.Errorf("remove empty metadata: %w", err)
@@ -177,12 +292,14 @@ func SaveMetadata(tool registry.Tool, scope config.Scope, meta *Metadata) error
 		return fmt.Errorf("ensure dir: %w", err)
 	}
 
+	meta.SchemaVersion = metadataSchemaVersion
+
 	data, err := json.MarshalIndent(meta, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshal metadata: %w", err)
 	}
 
-	err = os.WriteFile(path, data, filePermissions)
+	err = fsutil.WriteFile(path, data, filePermissions)
 	if err != nil {
 		return fmt.Errorf("write metadata: %w", err)
 	}
@@ -190,19 +307,50 @@ func SaveMetadata(tool registry.Tool, scope config.Scope, meta *Metadata) error
 	return nil
 }
 
-// ComputeHash computes an MD5 hash of the content.
-func ComputeHash(content string) string {
-	hash := md5.Sum([]byte(content)) //nolint:gosec // MD5 used for change detection, not security
+// UpdateMetadata loads the metadata for a tool and scope, passes it to fn and saves it,
+// holding a file lock throughout so concurrent skillsmith processes don't lose each other's changes.
+// Nothing is saved if fn returns an error, and backups taken by fn are deleted again.
+// Backups dropped by fn are only deleted once the metadata is saved.
+func UpdateMetadata(tool registry.Tool, scope config.Scope, fn func(meta *Metadata) error) error {
+	dir, err := GetStateDir(tool, scope)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, metadataLockName)
 
-	return hex.EncodeToString(hash[:])
-}
+	err = config.EnsureDir(path)
+	if err != nil {
+		return fmt.Errorf("ensure dir: %w", err)
+	}
 
-// ComputeFileHash reads a file and computes its MD5 hash.
-func ComputeFileHash(path string) (string, error) {
-	data, err := os.ReadFile(path) //nolint:gosec // path is from trusted source
+	lock, err := fsutil.LockFile(path)
 	if err != nil {
-		return "", fmt.Errorf("read file: %w", err)
+		return fmt.Errorf("lock metadata: %w", err)
 	}
 
-	return ComputeHash(string(data)), nil
+	defer func() { _ = lock.Unlock() }()
+
+	meta, err := LoadMetadata(tool, scope)
+	if err != nil {
+		return fmt.Errorf("failed to load metadata: %w", err)
+	}
+
+	err = fn(meta)
+	if err != nil {
+		meta.discardCreatedBackups()
+
+		return err
+	}
+
+	err = SaveMetadata(tool, scope, meta)
+	if err != nil {
+		meta.discardCreatedBackups()
+
+		return fmt.Errorf("failed to save metadata: %w", err)
+	}
+
+	meta.deleteDroppedBackups()
+
+	return nil
 }
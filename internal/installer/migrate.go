@@ -0,0 +1,109 @@
+package installer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/monke/skillsmith/internal/config"
+	"github.com/monke/skillsmith/internal/registry"
+)
+
+// legacyMetadata is the part of metadata before schema version 1 that changed:
+// hashes were MD5 and stored as "hash".
+type legacyMetadata struct {
+	Installed map[string]legacyInstalledItem `json:"installed"`
+	Backups   map[string][]legacyBackup      `json:"backups"`
+}
+
+type legacyInstalledItem struct {
+	Hash string `json:"hash"`
+}
+
+type legacyBackup struct {
+	Installed *legacyInstalledItem `json:"installed"`
+}
+
+// migrateMetadata upgrades metadata parsed from a file written before schema version 1.
+// The MD5 hash of each installed item is replaced with a SHA-256 hash if the installed files
+// still match it; otherwise the MD5 hash is kept as LegacyHash until the item is reinstalled.
+// The type and path are filled in where the installed file can be found. Where an item came
+// from was not recorded, so its source, commit and version stay empty.
+func migrateMetadata(meta *Metadata, data []byte, tool registry.Tool, scope config.Scope) error {
+	var legacy legacyMetadata
+
+	err := json.Unmarshal(data, &legacy)
+	if err != nil {
+		return fmt.Errorf("parse schema version %d: %w", meta.SchemaVersion, err)
+	}
+
+	for name, installed := range meta.Installed {
+		installed.LegacyHash = legacy.Installed[name].Hash
+		installed.Tool = tool
+
+		meta.Installed[name] = migrateInstalled(name, installed, tool, scope)
+	}
+
+	// Records in backups keep their MD5 hash, the files they describe are no longer installed
+	for name, history := range meta.Backups {
+		for i, b := range history {
+			if b.Installed == nil || i >= len(legacy.Backups[name]) || legacy.Backups[name][i].Installed == nil {
+				continue
+			}
+
+			b.Installed.LegacyHash = legacy.Backups[name][i].Installed.Hash
+			b.Installed.Tool = tool
+		}
+	}
+
+	meta.SchemaVersion = metadataSchemaVersion
+
+	return nil
+}
+
+// migrateInstalled finds the installed file of a record migrated from before schema version 1
+// and replaces its MD5 hash with a SHA-256 hash if the file is unchanged.
+func migrateInstalled(name string, installed InstalledItem, tool registry.Tool, scope config.Scope) InstalledItem {
+	// The item type wasn't recorded, so look for the file where each type would be installed
+	types := []registry.ItemType{registry.ItemTypeSkill, registry.ItemTypeAgent}
+	paths := make([]string, len(types))
+
+	for i, itemType := range types {
+		path, err := GetInstallPath(registry.Item{Name: name, Type: itemType}, tool, scope)
+		if err != nil {
+			return installed
+		}
+
+		paths[i] = path
+	}
+
+	for i, itemType := range types {
+		if !config.Exists(paths[i]) {
+			continue
+		}
+
+		// Tools without a place for agents install them as skills, which leaves the type unknown.
+		// So does a skill directory of a tool that has one: older versions installed Claude agents
+		// there, and MigrateLegacyAgent only moves records of unknown type or agents.
+		mayBeAgent := itemType == registry.ItemTypeSkill && filepath.Base(paths[i]) == "SKILL.md"
+		if paths[0] != paths[1] && !mayBeAgent {
+			installed.Type = itemType
+		}
+
+		installed.Path = paths[i]
+
+		probe := registry.Item{Name: name, Type: itemType}
+
+		md5Hash, err := computeInstalledHash(probe, tool, scope, paths[i], installed.Files, ComputeTreeHash)
+		if err == nil && md5Hash == installed.LegacyHash {
+			installed.Hash, err = computeInstalledHash(probe, tool, scope, paths[i], installed.Files, ComputeTreeSHA256)
+			if err == nil {
+				installed.LegacyHash = ""
+			}
+		}
+
+		break
+	}
+
+	return installed
+}
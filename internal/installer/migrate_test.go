@@ -0,0 +1,96 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/monke/skillsmith/internal/config"
+	"github.com/monke/skillsmith/internal/registry"
+)
+
+// TestMigrateLegacyAgentFromOldMetadata checks that a Claude agent installed as a skill
+// by a version that wrote metadata before schema version 1 is moved to the agents directory.
+func TestMigrateLegacyAgentFromOldMetadata(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	tool, scope := registry.ToolClaude, config.ScopeGlobal
+	agent := registry.Item{
+		Name:          "reviewer",
+		Type:          registry.ItemTypeAgent,
+		Description:   "Reviews code",
+		Body:          "Review the code.\n",
+		Compatibility: []registry.Tool{tool},
+	}
+
+	legacyPath, err := GetInstallPath(registry.Item{Name: agent.Name, Type: registry.ItemTypeSkill}, tool, scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	legacyContent := "---\nname: reviewer\n---\nReview the code.\n"
+	writeTestFile(t, legacyPath, legacyContent)
+
+	metaPath, err := GetMetadataPath(tool, scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Metadata as written before schema version 1: an MD5 hash and no type
+	writeTestFile(t, metaPath, `{"installed": {"reviewer": {"hash": "`+ComputeTreeHash(legacyContent, nil)+
+		`", "installed_at": "2025-01-01T00:00:00Z"}}}`)
+
+	migrated, err := MigrateLegacyAgent(agent, tool, scope)
+	if err != nil {
+		t.Fatalf("MigrateLegacyAgent: %v", err)
+	}
+
+	if !migrated {
+		t.Fatal("MigrateLegacyAgent did not migrate the agent")
+	}
+
+	agentPath, err := GetInstallPath(agent, tool, scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if filepath.Base(filepath.Dir(agentPath)) != "agents" {
+		t.Fatalf("agent install path %s is not in an agents directory", agentPath)
+	}
+
+	if !config.Exists(agentPath) {
+		t.Errorf("agent not installed at %s", agentPath)
+	}
+
+	if config.Exists(legacyPath) {
+		t.Errorf("legacy file %s was not removed", legacyPath)
+	}
+
+	meta, err := LoadMetadata(tool, scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installed, ok := meta.Get(agent.Name)
+	if !ok {
+		t.Fatal("agent missing from metadata")
+	}
+
+	if installed.Type != registry.ItemTypeAgent || installed.Path != agentPath {
+		t.Errorf("recorded type %q at %s, want agent at %s", installed.Type, installed.Path, agentPath)
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	err := os.MkdirAll(filepath.Dir(path), 0o750)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(path, []byte(content), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
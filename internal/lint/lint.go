@@ -0,0 +1,172 @@
+// Package lint validates registry directories for registry authors.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/monke/skillsmith/internal/registry"
+	"github.com/monke/skillsmith/internal/transformer"
+)
+
+var errNotDir = errors.New("registry path is not a directory")
+
+// Severity is the severity of a finding.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Finding is a single problem found in a registry.
+type Finding struct {
+	Path     string
+	Line     int // 1-based line number, or 0 if the finding applies to the whole file
+	Severity Severity
+	Message  string
+}
+
+// String formats the finding as path:line: severity: message.
+func (f Finding) String() string {
+	if f.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s: %s", f.Path, f.Line, f.Severity, f.Message)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", f.Path, f.Severity, f.Message)
+}
+
+// Report is the result of linting a registry.
+type Report struct {
+	// Items is the number of items that could be parsed.
+	Items int
+
+	// Findings are sorted by path and line.
+	Findings []Finding
+}
+
+// Count returns the number of findings with the given severity.
+func (r *Report) Count(severity Severity) int {
+	count := 0
+
+	for _, f := range r.Findings {
+		if f.Severity == severity {
+			count++
+		}
+	}
+
+	return count
+}
+
+// HasErrors returns true if any finding is an error.
+func (r *Report) HasErrors() bool {
+	return r.Count(SeverityError) > 0
+}
+
+// Run lints the registry directory at path.
+// Files are parsed strictly, so unknown frontmatter keys are reported as errors.
+func Run(path string) (*Report, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat registry path: %w", err)
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", errNotDir, path)
+	}
+
+	reg, err := registry.LoadFromFSWithOptions(os.DirFS(path), ".", registry.LoadOptions{Strict: true})
+	if err != nil {
+		return nil, fmt.Errorf("load registry: %w", err)
+	}
+
+	report := &Report{Items: len(reg.Items)}
+
+	for _, diag := range reg.Diagnostics {
+		report.add(diag.Path, diag.Line, SeverityError, diag.Reason)
+	}
+
+	seen := make(map[string]string) // name -> path of first definition
+
+	for i := range reg.Items {
+		item := &reg.Items[i]
+
+		for _, err := range registry.ValidateItem(item) {
+			report.add(item.SourcePath, 0, SeverityOf(err), err.Error())
+		}
+
+		// Render with a value for every variable to catch template errors and undeclared variables
+		if len(item.Vars) > 0 {
+			values := make(map[string]string, len(item.Vars))
+			for name := range item.Vars {
+				values[name] = name
+			}
+
+			_, err := transformer.Render(*item, values)
+			if err != nil {
+				report.add(item.SourcePath, 0, SeverityError, err.Error())
+			}
+		}
+
+		if item.Name == "" {
+			continue
+		}
+
+		if first, ok := seen[item.Name]; ok {
+			report.add(item.SourcePath, 0, SeverityError,
+				fmt.Sprintf("%v %q, already defined in %s", registry.ErrDuplicateName, item.Name, first))
+		} else {
+			seen[item.Name] = item.SourcePath
+		}
+	}
+
+	// Unqualified requirements may still come from another registry, so they're only warnings
+	for _, item := range reg.Items {
+		for _, req := range item.Requires {
+			name, _, _ := strings.Cut(req, "@")
+			if strings.Contains(name, "/") {
+				continue
+			}
+
+			if _, ok := seen[name]; !ok {
+				report.add(item.SourcePath, 0, SeverityWarning,
+					fmt.Sprintf("requires %q, which is not defined in this registry", req))
+			}
+		}
+	}
+
+	slices.SortStableFunc(report.Findings, func(a, b Finding) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+
+		return a.Line - b.Line
+	})
+
+	return report, nil
+}
+
+// add appends a finding to the report.
+func (r *Report) add(path string, line int, severity Severity, message string) {
+	r.Findings = append(r.Findings, Finding{
+		Path:     path,
+		Line:     line,
+		Severity: severity,
+		Message:  message,
+	})
+}
+
+// SeverityOf classifies an error returned by registry.ValidateItem.
+// Problems that don't break installation are warnings.
+func SeverityOf(err error) Severity {
+	switch {
+	case errors.Is(err, registry.ErrEmptyBody), errors.Is(err, registry.ErrUnknownTool):
+		// Tools may be declared by the consumer's config.yaml
+		return SeverityWarning
+	default:
+		return SeverityError
+	}
+}
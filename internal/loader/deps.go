@@ -0,0 +1,121 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/monke/skillsmith/internal/registry"
+)
+
+// Dependency errors.
+var (
+	ErrDependencyCycle   = errors.New("dependency cycle")
+	ErrMissingDependency = errors.New("missing dependency")
+)
+
+// visitState tracks the progress of the depth-first dependency walk.
+type visitState int
+
+const (
+	unvisited visitState = iota
+	visiting
+	visited
+)
+
+// ResolveDependencies resolves an item reference and everything it requires, transitively.
+// Items are ordered so that each one comes after its requirements, ending with the item itself.
+// Bundles are expanded into their members and are not part of the result.
+func (m *Manager) ResolveDependencies(ref string) ([]*registry.Item, error) {
+	resolver := &dependencyResolver{
+		mgr:   m,
+		state: make(map[string]visitState),
+	}
+
+	err := resolver.visit(ref, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolver.order, nil
+}
+
+// dependencyResolver walks the requires graph depth-first.
+type dependencyResolver struct {
+	mgr   *Manager
+	state map[string]visitState // by item name
+	order []*registry.Item
+}
+
+// visit resolves ref and its requirements. chain holds the names of the items that led here.
+func (r *dependencyResolver) visit(ref string, chain []string) error {
+	item, err := r.mgr.ResolveProjectItem(ref)
+	if err != nil {
+		if len(chain) == 0 {
+			return err
+		}
+
+		return fmt.Errorf("%w: %s requires %s: %w", ErrMissingDependency, chain[len(chain)-1], ref, err)
+	}
+
+	switch r.state[item.Name] {
+	case visited:
+		return nil
+	case visiting:
+		cycle := append(slices.Clone(chain[slices.Index(chain, item.Name):]), item.Name)
+
+		return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(cycle, " -> "))
+	case unvisited:
+	}
+
+	r.state[item.Name] = visiting
+	next := append(slices.Clone(chain), item.Name)
+
+	for _, req := range item.Requires {
+		err = r.visit(req, next)
+		if err != nil {
+			return err
+		}
+	}
+
+	r.state[item.Name] = visited
+
+	if item.Type != registry.ItemTypeBundle {
+		r.order = append(r.order, item)
+	}
+
+	return nil
+}
+
+// requirementNames returns the names of the items that must be installed before item:
+// its direct requirements, with bundles replaced by their members.
+func (m *Manager) requirementNames(item *registry.Item) []string {
+	var names []string
+
+	seen := map[string]bool{item.Name: true}
+	pending := slices.Clone(item.Requires)
+
+	for len(pending) > 0 {
+		ref := pending[0]
+		pending = pending[1:]
+
+		req, err := m.ResolveProjectItem(ref)
+		if err != nil || seen[req.Name] {
+			// Unresolvable requirements are reported by ResolveDependencies
+			continue
+		}
+
+		seen[req.Name] = true
+
+		if req.Type == registry.ItemTypeBundle {
+			pending = append(pending, req.Requires...)
+
+			continue
+		}
+
+		names = append(names, req.Name)
+	}
+
+	return names
+}
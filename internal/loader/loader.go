@@ -2,13 +2,44 @@
 package loader
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
 
+	"github.com/monke/skillsmith/internal/adapter"
 	"github.com/monke/skillsmith/internal/config"
 	"github.com/monke/skillsmith/internal/project"
 	"github.com/monke/skillsmith/internal/registry"
 )
 
+// OfflineEnv is the environment variable that forces offline mode when set to a true value.
+const OfflineEnv = "SKILLSMITH_OFFLINE"
+
+// ErrOffline is returned for operations that need the network in offline mode.
+var ErrOffline = errors.New("not available in offline mode")
+
+// offline forces Git registries to load from their cache only.
+var offline atomic.Bool
+
+// SetOffline enables or disables offline mode for all subsequent loads.
+func SetOffline(v bool) {
+	offline.Store(v)
+}
+
+// IsOffline returns true if offline mode was enabled with SetOffline or SKILLSMITH_OFFLINE.
+func IsOffline() bool {
+	if offline.Load() {
+		return true
+	}
+
+	v, err := strconv.ParseBool(os.Getenv(OfflineEnv))
+
+	return err == nil && v
+}
+
 // LoadFromConfig creates a MultiRegistry from the user's config.
 // Sources are loaded in order with last source winning for duplicates:
 // 1. Builtin (embedded) - lowest priority
@@ -26,23 +57,40 @@ func LoadFromConfigWithProject(includeProject bool) (*registry.MultiRegistry, er
 		return nil, fmt.Errorf("load config: %w", err)
 	}
 
+	// Make tools declared in config.yaml available as install targets
+	err = adapter.RegisterDefinitions(cfg.Tools)
+	if err != nil {
+		return nil, fmt.Errorf("register tools: %w", err)
+	}
+
+	ttl, err := cfg.GetFetchTTL()
+	if err != nil {
+		return nil, err
+	}
+
 	multi := registry.NewMultiRegistry()
 
 	// 1. Add builtin source first (lowest priority, can be overridden)
 	multi.AddSource(registry.NewEmbeddedSource("builtin"))
 
 	// 2. Add global configured sources
-	addRegistrySources(multi, cfg.Registries)
+	addRegistrySources(multi, cfg.Registries, ttl)
+
+	var projectVars map[string]string
 
 	// 3. Add project-specific sources (highest priority)
 	if includeProject {
 		projectCfg, _, err := project.Load()
 		if err == nil && projectCfg != nil {
-			addRegistrySources(multi, projectCfg.Registries)
+			addRegistrySources(multi, projectCfg.Registries, ttl)
+			projectVars = projectCfg.Vars
 		}
 		// Ignore error - project config is optional
 	}
 
+	// Project values override global ones when rendering item bodies for local installs
+	adapter.SetVars(cfg.Vars, projectVars)
+
 	// Load all sources
 	err = multi.Load()
 	if err != nil {
@@ -68,7 +116,8 @@ func LoadBuiltinOnly() (*registry.MultiRegistry, error) {
 }
 
 // addRegistrySources adds registry sources to a MultiRegistry.
-func addRegistrySources(multi *registry.MultiRegistry, sources []config.RegistrySource) {
+// Git sources only fetch once their last fetch is older than ttl, and never in offline mode.
+func addRegistrySources(multi *registry.MultiRegistry, sources []config.RegistrySource, ttl time.Duration) {
 	for _, src := range sources {
 		if !src.IsEnabled() {
 			continue
@@ -78,7 +127,164 @@ func addRegistrySources(multi *registry.MultiRegistry, sources []config.Registry
 		case src.IsLocal():
 			multi.AddSource(registry.NewLocalSource(src.Name, src.Path))
 		case src.IsGit():
-			multi.AddSource(registry.NewGitSource(src.Name, src.URL))
+			gitSrc := registry.NewGitSource(src.Name, src.URL, src.Ref)
+			gitSrc.SetTTL(ttl)
+			gitSrc.SetOffline(IsOffline())
+			multi.AddSource(gitSrc)
 		}
 	}
 }
+
+// RegistryUpdate is the result of updating a single Git registry.
+type RegistryUpdate struct {
+	Name      string
+	OldCommit string // empty if the registry was not cached before
+	NewCommit string
+	Error     error
+}
+
+// UpdateRegistries fetches the named Git registries, or all of them if names is empty.
+// Both global and project registries are considered.
+func UpdateRegistries(names []string) ([]RegistryUpdate, error) {
+	if IsOffline() {
+		return nil, ErrOffline
+	}
+
+	sources, err := selectGitSources(names)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make([]RegistryUpdate, 0, len(sources))
+
+	for _, src := range sources {
+		oldCommit, newCommit, err := src.Update()
+		updates = append(updates, RegistryUpdate{
+			Name:      src.Name(),
+			OldCommit: oldCommit,
+			NewCommit: newCommit,
+			Error:     err,
+		})
+	}
+
+	return updates, nil
+}
+
+// CleanRegistries removes the caches of the named Git registries.
+// If names is empty, all cached repositories are removed.
+// Returns the names of the cleaned registries.
+func CleanRegistries(names []string) ([]string, error) {
+	if len(names) == 0 {
+		err := registry.ClearAllCaches()
+		if err != nil {
+			return nil, fmt.Errorf("clear caches: %w", err)
+		}
+
+		return nil, nil
+	}
+
+	sources, err := selectGitSources(names)
+	if err != nil {
+		return nil, err
+	}
+
+	cleaned := make([]string, 0, len(sources))
+
+	for _, src := range sources {
+		err = src.Clear()
+		if err != nil {
+			return cleaned, fmt.Errorf("clear %s: %w", src.Name(), err)
+		}
+
+		cleaned = append(cleaned, src.Name())
+	}
+
+	return cleaned, nil
+}
+
+// selectGitSources returns the configured Git sources matching names, or all if names is empty.
+// Project registries take precedence over global registries with the same name.
+func selectGitSources(names []string) ([]*registry.GitSource, error) {
+	byName, order, err := configuredSources()
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]*registry.GitSource, 0, len(byName))
+
+	if len(names) == 0 {
+		// Without explicit names, update every enabled Git registry.
+		for _, name := range order {
+			src := byName[name]
+			if src.IsGit() && src.IsEnabled() {
+				sources = append(sources, registry.NewGitSource(src.Name, src.URL, src.Ref))
+			}
+		}
+
+		return sources, nil
+	}
+
+	for _, name := range names {
+		src, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrRegistryNotFound, name)
+		}
+
+		if !src.IsGit() {
+			return nil, fmt.Errorf("%w: %s", ErrNotGitRegistry, name)
+		}
+
+		sources = append(sources, registry.NewGitSource(src.Name, src.URL, src.Ref))
+	}
+
+	return sources, nil
+}
+
+// LocalRegistryPath returns the directory of the configured local registry with the given name.
+func LocalRegistryPath(name string) (string, error) {
+	byName, _, err := configuredSources()
+	if err != nil {
+		return "", err
+	}
+
+	src, ok := byName[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrRegistryNotFound, name)
+	}
+
+	if !src.IsLocal() {
+		return "", fmt.Errorf("%w: %s", ErrNotLocalRegistry, name)
+	}
+
+	return src.Path, nil
+}
+
+// configuredSources returns the global and project registry sources by name,
+// along with their names in config order.
+// Project registries take precedence over global registries with the same name.
+func configuredSources() (map[string]config.RegistrySource, []string, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("load config: %w", err)
+	}
+
+	configured := cfg.Registries
+
+	projectCfg, _, err := project.Load()
+	if err == nil && projectCfg != nil {
+		configured = append(configured, projectCfg.Registries...)
+	}
+
+	byName := make(map[string]config.RegistrySource)
+	order := make([]string, 0, len(configured))
+
+	for _, src := range configured {
+		if _, seen := byName[src.Name]; !seen {
+			order = append(order, src.Name)
+		}
+
+		byName[src.Name] = src
+	}
+
+	return byName, order, nil
+}
@@ -6,11 +6,14 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/monke/skillsmith/internal/adapter"
 	"github.com/monke/skillsmith/internal/config"
 	"github.com/monke/skillsmith/internal/installer"
 	"github.com/monke/skillsmith/internal/project"
 	"github.com/monke/skillsmith/internal/registry"
+	"github.com/monke/skillsmith/internal/transformer"
 )
 
 // Manager errors.
@@ -23,12 +26,67 @@ var (
 	ErrCannotRemoveBuiltin = errors.New("cannot remove the builtin registry")
 	ErrRegistryNotFound    = errors.New("registry not found")
 	ErrInvalidURL          = errors.New("invalid git URL")
+	ErrNotGitRegistry      = errors.New("registry is not a git registry")
+	ErrNotLocalRegistry    = errors.New("registry is not a local registry")
+	ErrRegistryNotLoaded   = errors.New("referenced registry is not configured")
+	ErrRegistryFailed      = errors.New("referenced registry failed to load")
+	ErrRefMismatch         = errors.New("item does not match the required ref")
+	ErrNotLocked           = errors.New("item is not in the lock file")
+	ErrLockMismatch        = errors.New("item does not match the lock file")
+	ErrRenderFailed        = errors.New("item body could not be rendered")
+	ErrRequirementFailed   = errors.New("required item was not installed")
+	ErrRequirementConflict = errors.New("required item conflicts with a project item")
 )
 
 // Manager provides the main API for working with the registry.
 // It coordinates loading, installation, and configuration.
 type Manager struct {
-	registry *registry.Registry
+	registry   *registry.Registry
+	loadErrors []error        // sources that failed to load
+	warnings   []error        // problems that didn't stop an operation, see Warnings
+	statuses   []SourceStatus // per-source load outcome, in load order
+}
+
+// SourceStatus is the load status of a single registry source.
+type SourceStatus struct {
+	Name        string
+	ItemCount   int
+	Duration    time.Duration
+	Error       error     // nil if the source loaded successfully
+	LastUpdated time.Time // last fetch of Git sources; zero for other sources
+	Ref         string    // configured ref of Git sources
+	Diagnostics []registry.Diagnostic
+}
+
+// OK returns true if the source loaded successfully.
+func (s SourceStatus) OK() bool {
+	return s.Error == nil
+}
+
+// sourceStatuses collects the load status of every source of a loaded MultiRegistry.
+func sourceStatuses(multi *registry.MultiRegistry) []SourceStatus {
+	sources := multi.Sources()
+	results := multi.Results()
+	statuses := make([]SourceStatus, 0, len(results))
+
+	for i, result := range results {
+		status := SourceStatus{
+			Name:        result.Name,
+			ItemCount:   result.ItemCount,
+			Duration:    result.Duration,
+			Error:       result.Err,
+			Diagnostics: result.Diagnostics,
+		}
+
+		if gitSrc, ok := sources[i].(*registry.GitSource); ok {
+			status.LastUpdated, _ = gitSrc.LastFetched()
+			status.Ref = gitSrc.Ref()
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
 }
 
 // NewManager creates a new Manager, loading registries from config.
@@ -38,9 +96,13 @@ func NewManager() (*Manager, error) {
 		return nil, fmt.Errorf("load registry: %w", err)
 	}
 
-	return &Manager{
-		registry: multi.Registry(),
-	}, nil
+	mgr := &Manager{
+		registry:   multi.Registry(),
+		loadErrors: multi.Errors(),
+		statuses:   sourceStatuses(multi),
+	}
+
+	return mgr, nil
 }
 
 // NewManagerWithRegistry creates a Manager with a pre-loaded registry.
@@ -51,6 +113,32 @@ func NewManagerWithRegistry(reg *registry.Registry) *Manager {
 	}
 }
 
+// migrateLegacyAgents moves Claude agents that were installed as skills by older
+// versions to their subagent location, before items are installed or updated for a tool.
+// Read-only commands never migrate. This is best effort: failures leave the
+// legacy files in place and are reported as warnings.
+func (m *Manager) migrateLegacyAgents(tool registry.Tool, scope config.Scope) {
+	if tool != registry.ToolClaude {
+		return
+	}
+
+	for _, item := range m.registry.ByToolAndType(tool, registry.ItemTypeAgent) {
+		_, err := installer.MigrateLegacyAgent(item, tool, scope)
+		if err != nil {
+			m.warnings = append(m.warnings, fmt.Errorf("migrate legacy agent %s: %w", item.Name, err))
+		}
+	}
+}
+
+// Warnings returns the problems that didn't stop the operations run since the last call,
+// such as legacy agents that couldn't be migrated, and clears them.
+func (m *Manager) Warnings() []error {
+	warnings := m.warnings
+	m.warnings = nil
+
+	return warnings
+}
+
 // Registry returns the underlying registry.
 func (m *Manager) Registry() *registry.Registry {
 	return m.registry
@@ -65,19 +153,158 @@ func (m *Manager) Reload() error {
 	}
 
 	m.registry = multi.Registry()
+	m.loadErrors = multi.Errors()
+	m.statuses = sourceStatuses(multi)
+
+	return nil
+}
+
+// SourceStatuses returns the load status of each registry source, in load order.
+func (m *Manager) SourceStatuses() []SourceStatus {
+	return m.statuses
+}
+
+// FailedSources returns the load status of the sources that failed to load.
+func (m *Manager) FailedSources() []SourceStatus {
+	var failed []SourceStatus
+
+	for _, status := range m.statuses {
+		if !status.OK() {
+			failed = append(failed, status)
+		}
+	}
+
+	return failed
+}
+
+// sourceStatus returns the status of the first loaded source with the given name.
+func (m *Manager) sourceStatus(name string) *SourceStatus {
+	for i := range m.statuses {
+		if m.statuses[i].Name == name {
+			return &m.statuses[i]
+		}
+	}
 
 	return nil
 }
 
+// LoadErrors returns the errors of registry sources that failed to load.
+// Items from the remaining sources are still available.
+func (m *Manager) LoadErrors() []error {
+	return m.loadErrors
+}
+
 // GetItem returns a single item by name.
+// The name may be qualified with a source, like "builtin/committing",
+// to get that source's definition even if another source overrides it.
 func (m *Manager) GetItem(name string) (*registry.Item, error) {
-	for i := range m.registry.Items {
-		if m.registry.Items[i].Name == name {
-			return &m.registry.Items[i], nil
+	item, ok := m.registry.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrItemNotFound, name)
+	}
+
+	return item, nil
+}
+
+// ResolveProjectItem resolves an item reference from the project config,
+// written as [registry/]name[@ref]. A named registry must be configured and loaded,
+// and a ref must match the registry's configured ref or the commit the item was loaded from.
+func (m *Manager) ResolveProjectItem(s string) (*registry.Item, error) {
+	ref, err := project.ParseRef(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if ref.Registry != "" {
+		status := m.sourceStatus(ref.Registry)
+		if status == nil {
+			return nil, fmt.Errorf("%w: %s", ErrRegistryNotLoaded, ref.Registry)
 		}
+
+		if !status.OK() {
+			return nil, fmt.Errorf("%w: %s: %w", ErrRegistryFailed, ref.Registry, status.Error)
+		}
+	}
+
+	item, err := m.GetItem(ref.Qualified())
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, fmt.Errorf("%w: %s", ErrItemNotFound, name)
+	if ref.Ref != "" {
+		err = m.checkRef(item, ref.Ref)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return item, nil
+}
+
+// checkRef checks that an item was loaded at the given ref.
+func (m *Manager) checkRef(item *registry.Item, ref string) error {
+	if item.Commit == "" {
+		return fmt.Errorf("%w: %s is from %s, which is not a git registry", ErrRefMismatch, item.Name, item.Source)
+	}
+
+	// A SHA prefix must be long enough to be meaningful
+	const minSHAPrefix = 7
+
+	if len(ref) >= minSHAPrefix && strings.HasPrefix(item.Commit, ref) {
+		return nil
+	}
+
+	status := m.sourceStatus(item.Source)
+	if status != nil && status.Ref == ref {
+		return nil
+	}
+
+	configured := "default branch"
+	if status != nil && status.Ref != "" {
+		configured = status.Ref
+	}
+
+	return fmt.Errorf("%w: %s requires %s, but %s is at %s (%.7s)",
+		ErrRefMismatch, item.Name, ref, item.Source, configured, item.Commit)
+}
+
+// resolveReason describes why a project item could not be resolved.
+func resolveReason(err error) string {
+	switch {
+	case errors.Is(err, ErrDependencyCycle):
+		return "has a dependency cycle"
+	case errors.Is(err, ErrMissingDependency):
+		return "has a missing dependency"
+	case errors.Is(err, ErrRequirementConflict):
+		return "conflicts with another project item"
+	case errors.Is(err, transformer.ErrMissingVars):
+		return "is missing required variables"
+	case errors.Is(err, ErrRenderFailed):
+		return "could not be rendered"
+	case errors.Is(err, ErrItemNotFound):
+		return "not found in registry"
+	default:
+		return "could not be resolved"
+	}
+}
+
+// Overridden returns the definitions of an item hidden by higher-priority sources,
+// lowest priority first.
+func (m *Manager) Overridden(name string) []registry.Item {
+	return m.registry.Overridden(name)
+}
+
+// OverriddenSources returns the sources whose definition of an item is hidden,
+// highest priority first.
+func (m *Manager) OverriddenSources(name string) []string {
+	shadowed := m.registry.Overridden(name)
+	sources := make([]string, 0, len(shadowed))
+
+	for i := len(shadowed) - 1; i >= 0; i-- {
+		sources = append(sources, shadowed[i].Source)
+	}
+
+	return sources
 }
 
 // ListItems returns items filtered by tool, with optional type filter.
@@ -90,6 +317,7 @@ func (m *Manager) ListItems(tool registry.Tool, itemType registry.ItemType) []re
 }
 
 // ListItemsWithState returns items with their installation state.
+// Bundles are left out: they have no state of their own, their members are installed instead.
 func (m *Manager) ListItemsWithState(
 	tool registry.Tool, scope config.Scope, itemType registry.ItemType,
 ) []installer.ItemWithState {
@@ -97,6 +325,10 @@ func (m *Manager) ListItemsWithState(
 	result := make([]installer.ItemWithState, 0, len(items))
 
 	for _, item := range items {
+		if item.Type == registry.ItemTypeBundle {
+			continue
+		}
+
 		state, path, _ := installer.GetItemState(item, tool, scope)
 		result = append(result, installer.ItemWithState{
 			Item:        item,
@@ -108,35 +340,106 @@ func (m *Manager) ListItemsWithState(
 	return result
 }
 
-// Install installs an item.
+// Install installs an item together with everything it requires.
+// Requirements that are already installed are left alone; force only applies to the item itself.
+// Installing a bundle installs its members, and the returned path is empty.
 func (m *Manager) Install(
 	itemName string, tool registry.Tool, scope config.Scope, force bool,
 ) (*installer.Result, string, error) {
-	item, err := m.GetItem(itemName)
+	target, err := m.ResolveProjectItem(itemName)
 	if err != nil {
 		return nil, "", err
 	}
 
-	// Check compatibility
-	if !item.IsCompatibleWith(tool) {
-		return &installer.Result{Success: false}, "", fmt.Errorf("%w: %s", ErrItemNotCompatible, tool)
+	items, err := m.ResolveDependencies(itemName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Check compatibility up front, so nothing is installed without its requirements
+	for _, item := range items {
+		if item.IsCompatibleWith(tool) {
+			continue
+		}
+
+		if item.Name == target.Name {
+			return &installer.Result{Success: false}, "", fmt.Errorf("%w: %s", ErrItemNotCompatible, tool)
+		}
+
+		return &installer.Result{Success: false}, "",
+			fmt.Errorf("%w: %s requires %s, which is not available for %s", ErrItemNotCompatible, target.Name, item.Name, tool)
+	}
+
+	m.migrateLegacyAgents(tool, scope)
+
+	result := &installer.Result{Success: false}
+	isBundle := target.Type == registry.ItemTypeBundle
+
+	var targetPath string
+
+	for _, item := range items {
+		isTarget := isBundle || item.Name == target.Name
+
+		path, err := installer.GetInstallPath(*item, tool, scope)
+		if err != nil {
+			return nil, "", fmt.Errorf("get install path: %w", err)
+		}
+
+		itemResult, err := installer.Install(*item, tool, scope, force && isTarget)
+		if err != nil {
+			return nil, path, fmt.Errorf("install %s: %w", item.Name, err)
+		}
+
+		if isTarget {
+			result.Success = result.Success || itemResult.Success
+		}
+
+		if item.Name == target.Name {
+			targetPath = path
+		}
+	}
+
+	return result, targetPath, nil
+}
+
+// Merge updates an installed item to the registry version, keeping local changes.
+// See installer.Merge.
+func (m *Manager) Merge(
+	itemName string, tool registry.Tool, scope config.Scope,
+) (*installer.Result, string, error) {
+	item, err := m.GetItem(itemName)
+	if err != nil {
+		return nil, "", err
 	}
 
-	// Get path for result
 	path, err := installer.GetInstallPath(*item, tool, scope)
 	if err != nil {
 		return nil, "", fmt.Errorf("get install path: %w", err)
 	}
 
-	// Install
-	result, err := installer.Install(*item, tool, scope, force)
+	result, err := installer.Merge(*item, tool, scope)
 	if err != nil {
-		return nil, path, fmt.Errorf("install: %w", err)
+		return nil, path, fmt.Errorf("merge: %w", err)
 	}
 
 	return result, path, nil
 }
 
+// Diff returns a unified diff from an installed item to its current registry version.
+// See installer.Diff.
+func (m *Manager) Diff(itemName string, tool registry.Tool, scope config.Scope) (string, error) {
+	item, err := m.GetItem(itemName)
+	if err != nil {
+		return "", err
+	}
+
+	if !item.IsCompatibleWith(tool) {
+		return "", fmt.Errorf("%w: %s", ErrItemNotCompatible, tool)
+	}
+
+	return installer.Diff(*item, tool, scope)
+}
+
 // Uninstall removes an installed item.
 func (m *Manager) Uninstall(
 	itemName string, tool registry.Tool, scope config.Scope,
@@ -161,13 +464,48 @@ func (m *Manager) Uninstall(
 	return result, path, nil
 }
 
+// History returns the backups of an installed item, oldest first.
+func (m *Manager) History(itemName string, tool registry.Tool, scope config.Scope) ([]installer.Backup, error) {
+	item, err := m.GetItem(itemName)
+	if err != nil {
+		return nil, err
+	}
+
+	return installer.History(*item, tool, scope)
+}
+
+// Rollback restores a backup of an item, or the latest one if id is empty.
+// See installer.Rollback.
+func (m *Manager) Rollback(
+	itemName string, tool registry.Tool, scope config.Scope, id string,
+) (*installer.Backup, string, error) {
+	item, err := m.GetItem(itemName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	path, err := installer.GetInstallPath(*item, tool, scope)
+	if err != nil {
+		return nil, "", fmt.Errorf("get install path: %w", err)
+	}
+
+	restored, err := installer.Rollback(*item, tool, scope, id)
+	if err != nil {
+		return nil, path, fmt.Errorf("rollback: %w", err)
+	}
+
+	return restored, path, nil
+}
+
 // RegistryInfo represents a configured registry source.
 type RegistryInfo struct {
 	Name    string
 	Type    string // "builtin", "local", "git"
 	Path    string
 	URL     string
+	Ref     string
 	Enabled bool
+	Status  *SourceStatus // nil if the registry was not loaded, e.g. because it is disabled
 }
 
 // ListRegistries returns all configured registry sources.
@@ -183,6 +521,7 @@ func (m *Manager) ListRegistries() ([]RegistryInfo, error) {
 			Name:    "builtin",
 			Type:    "builtin",
 			Enabled: true,
+			Status:  m.sourceStatus("builtin"),
 		},
 	}
 
@@ -191,9 +530,14 @@ func (m *Manager) ListRegistries() ([]RegistryInfo, error) {
 			Name:    reg.Name,
 			Path:    reg.Path,
 			URL:     reg.URL,
+			Ref:     reg.Ref,
 			Enabled: reg.IsEnabled(),
 		}
 
+		if info.Enabled {
+			info.Status = m.sourceStatus(reg.Name)
+		}
+
 		switch {
 		case reg.IsLocal():
 			info.Type = "local"
@@ -309,7 +653,8 @@ func (m *Manager) RemoveRegistry(name string) error {
 }
 
 // AddGitRegistry adds a new Git registry source.
-func (m *Manager) AddGitRegistry(name, url string) error {
+// The ref may be a tag, branch or commit SHA; an empty ref tracks the default branch.
+func (m *Manager) AddGitRegistry(name, url, ref string) error {
 	// Basic URL validation
 	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "git@") &&
 		!strings.HasPrefix(url, "http://") {
@@ -335,6 +680,7 @@ func (m *Manager) AddGitRegistry(name, url string) error {
 	cfg.Registries = append(cfg.Registries, config.RegistrySource{
 		Name: name,
 		URL:  url,
+		Ref:  ref,
 		Type: "git",
 	})
 
@@ -351,11 +697,15 @@ type ProjectInstallResult struct {
 	ItemName string
 	ItemType registry.ItemType
 	Tool     registry.Tool
+	State    installer.ItemState
 	Success  bool
 	Path     string
 	Error    error
 	Skipped  bool // true if item was skipped (not compatible, already installed, etc.)
 	Reason   string
+
+	// Installed is the install record of an installed item, reported by GetProjectStatus.
+	Installed *installer.InstalledItem
 }
 
 // InstallProjectItems installs all items defined in the project config.
@@ -364,57 +714,290 @@ func (m *Manager) InstallProjectItems(
 	projectCfg *project.Config,
 	scope config.Scope,
 	force bool,
+) []ProjectInstallResult {
+	return m.installProjectItems(projectCfg, scope, force, nil)
+}
+
+// InstallProjectItemsFrozen installs all items defined in the project config,
+// refusing to install any item whose source, commit or content hash differs from the lock.
+func (m *Manager) InstallProjectItemsFrozen(
+	projectCfg *project.Config,
+	lock *project.Lock,
+	scope config.Scope,
+	force bool,
+) []ProjectInstallResult {
+	return m.installProjectItems(projectCfg, scope, force, lock)
+}
+
+// installProjectItems installs all project items, verifying them against lock if it is non-nil.
+func (m *Manager) installProjectItems(
+	projectCfg *project.Config,
+	scope config.Scope,
+	force bool,
+	lock *project.Lock,
 ) []ProjectInstallResult {
 	results := make([]ProjectInstallResult, 0)
 
 	// Determine which tools to install for
 	tools := m.getTargetTools(projectCfg)
 
-	// Install skills
-	for _, skillName := range projectCfg.Skills {
+	for _, tool := range tools {
+		m.migrateLegacyAgents(tool, scope)
+	}
+
+	// Results of the items installed so far by tool, so dependents can check their requirements
+	done := make(map[registry.Tool]map[string]ProjectInstallResult, len(tools))
+	for _, tool := range tools {
+		done[tool] = make(map[string]ProjectInstallResult)
+	}
+
+	for _, entry := range m.planProjectItems(projectCfg) {
 		for _, tool := range tools {
-			result := m.installProjectItem(skillName, registry.ItemTypeSkill, tool, scope, force)
+			result := m.installProjectItem(entry, tool, scope, force, lock, done[tool])
+
+			if entry.item != nil && entry.item.Type == entry.itemType {
+				done[tool][entry.item.Name] = result
+			}
+
 			results = append(results, result)
 		}
 	}
 
-	// Install agents
-	for _, agentName := range projectCfg.Agents {
+	return results
+}
+
+// projectEntry is an item to install for a project.
+type projectEntry struct {
+	name     string            // reference as written in the project config, or the name of a requirement
+	itemType registry.ItemType // type the entry is listed as
+	item     *registry.Item    // nil if the reference could not be resolved
+	requires []string          // names of the items that must be installed first
+	err      error
+}
+
+// planProjectItems expands the skills, agents and bundles of a project into the items to install.
+// Requirements come before the items that need them, bundles are replaced by their members,
+// and each item appears once. Items listed in the project are resolved first, so a requirement
+// of the same name is the listed item; if it resolves to a different source or commit,
+// the item that requires it is reported as a conflict.
+func (m *Manager) planProjectItems(projectCfg *project.Config) []projectEntry {
+	groups := []struct {
+		refs     []string
+		itemType registry.ItemType
+	}{
+		{projectCfg.Skills, registry.ItemTypeSkill},
+		{projectCfg.Agents, registry.ItemTypeAgent},
+		{projectCfg.Bundles, registry.ItemTypeBundle},
+	}
+
+	var roots []projectEntry
+
+	listed := make(map[string]listedItem)
+
+	for _, group := range groups {
+		for _, ref := range group.refs {
+			root, err := m.ResolveProjectItem(ref)
+			if err != nil {
+				roots = append(roots, projectEntry{name: ref, itemType: group.itemType, err: err})
+
+				continue
+			}
+
+			entry := projectEntry{name: ref, itemType: group.itemType, item: root}
+
+			if root.Type == group.itemType && root.Type != registry.ItemTypeBundle {
+				if other, ok := listed[root.Name]; ok && !sameOrigin(other.item, root) {
+					entry.err = fmt.Errorf("%w: %s is also listed as %s, from %s",
+						ErrRequirementConflict, ref, other.ref, itemOrigin(other.item))
+				} else if !ok {
+					listed[root.Name] = listedItem{ref: ref, item: root}
+				}
+			}
+
+			roots = append(roots, entry)
+		}
+	}
+
+	var entries []projectEntry
+
+	seen := make(map[string]bool)
+
+	for _, root := range roots {
+		// Unresolved, or listed under the wrong type, reported when installing
+		if root.err != nil || root.item.Type != root.itemType {
+			entries = append(entries, root)
+
+			continue
+		}
+
+		items, err := m.ResolveDependencies(root.name)
+		if err == nil {
+			err = checkListed(root.item, items, listed)
+		}
+
+		if err != nil {
+			entries = append(entries, projectEntry{name: root.name, itemType: root.itemType, err: err})
+
+			continue
+		}
+
+		for _, item := range items {
+			if seen[item.Name] {
+				continue
+			}
+
+			seen[item.Name] = true
+
+			entry := projectEntry{name: item.Name, itemType: item.Type, item: item, requires: m.requirementNames(item)}
+			if l, ok := listed[item.Name]; ok {
+				entry.name = l.ref
+			}
+
+			// Check variables up front, so a missing value is reported instead of a failed install.
+			// Project items are installed locally.
+			_, err = adapter.Render(*item, config.ScopeLocal)
+			if err != nil {
+				entry.err = fmt.Errorf("%w: %w", ErrRenderFailed, err)
+			}
+
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// listedItem is a skill or agent listed in the project config, by the reference it is listed as.
+type listedItem struct {
+	ref  string
+	item *registry.Item
+}
+
+// checkListed checks that the requirements of root that share a name with an item listed in
+// the project resolve to that same item.
+func checkListed(root *registry.Item, items []*registry.Item, listed map[string]listedItem) error {
+	for _, item := range items {
+		l, ok := listed[item.Name]
+		if !ok || item.Name == root.Name || sameOrigin(l.item, item) {
+			continue
+		}
+
+		return fmt.Errorf("%w: %s requires %s from %s, but the project lists %s from %s",
+			ErrRequirementConflict, root.Name, item.Name, itemOrigin(item), l.ref, itemOrigin(l.item))
+	}
+
+	return nil
+}
+
+// sameOrigin reports whether two items were loaded from the same source at the same commit.
+func sameOrigin(a, b *registry.Item) bool {
+	return a.Source == b.Source && a.Commit == b.Commit
+}
+
+// itemOrigin describes where an item was loaded from, as source or source@commit.
+func itemOrigin(item *registry.Item) string {
+	if item.Commit == "" {
+		return item.Source
+	}
+
+	return fmt.Sprintf("%s@%.7s", item.Source, item.Commit)
+}
+
+// LockProjectItems resolves all items defined in the project config and
+// returns a lock recording their source, commit and content hash per tool.
+// Items that cannot be resolved or are not compatible with a tool are left out.
+func (m *Manager) LockProjectItems(projectCfg *project.Config) (*project.Lock, error) {
+	lock := project.NewLock()
+	tools := m.getTargetTools(projectCfg)
+
+	for _, entry := range m.planProjectItems(projectCfg) {
+		item := entry.item
+		if entry.err != nil || item.Type != entry.itemType {
+			continue
+		}
+
 		for _, tool := range tools {
-			result := m.installProjectItem(agentName, registry.ItemTypeAgent, tool, scope, force)
-			results = append(results, result)
+			if !item.IsCompatibleWith(tool) {
+				continue
+			}
+
+			hash, err := installer.ContentHash(*item, tool)
+			if err != nil {
+				return nil, fmt.Errorf("hash %s for %s: %w", item.Name, tool, err)
+			}
+
+			lock.Set(project.LockedItem{
+				Name:   item.Name,
+				Type:   item.Type,
+				Tool:   tool,
+				Source: item.Source,
+				Commit: item.Commit,
+				Hash:   hash,
+			})
 		}
 	}
 
-	return results
+	return lock, nil
+}
+
+// verifyLocked checks that an item resolves to exactly what the lock recorded.
+func verifyLocked(item *registry.Item, tool registry.Tool, lock *project.Lock) error {
+	locked, ok := lock.Get(item.Name, tool)
+	if !ok {
+		return fmt.Errorf("%w: %s (%s)", ErrNotLocked, item.Name, tool)
+	}
+
+	if locked.Source != item.Source {
+		return fmt.Errorf("%w: source is %s, locked %s", ErrLockMismatch, item.Source, locked.Source)
+	}
+
+	if locked.Commit != item.Commit {
+		return fmt.Errorf("%w: commit is %s, locked %s", ErrLockMismatch, item.Commit, locked.Commit)
+	}
+
+	hash, err := installer.ContentHash(*item, tool)
+	if err != nil {
+		return fmt.Errorf("hash content: %w", err)
+	}
+
+	if locked.Hash != hash {
+		return fmt.Errorf("%w: content hash is %s, locked %s", ErrLockMismatch, hash, locked.Hash)
+	}
+
+	return nil
 }
 
 // installProjectItem installs a single item for a tool.
+// If lock is non-nil, the item must match its locked entry to be installed.
+// done holds the results of the items already installed for the tool; the item is
+// only installed if all of its requirements were.
 func (m *Manager) installProjectItem(
-	name string,
-	itemType registry.ItemType,
+	entry projectEntry,
 	tool registry.Tool,
 	scope config.Scope,
 	force bool,
+	lock *project.Lock,
+	done map[string]ProjectInstallResult,
 ) ProjectInstallResult {
 	result := ProjectInstallResult{
-		ItemName: name,
-		ItemType: itemType,
+		ItemName: entry.name,
+		ItemType: entry.itemType,
 		Tool:     tool,
 	}
 
-	item, err := m.GetItem(name)
-	if err != nil {
-		result.Error = err
-		result.Reason = "not found in registry"
+	if entry.err != nil {
+		result.Error = entry.err
+		result.Reason = resolveReason(entry.err)
 
 		return result
 	}
 
+	item := entry.item
+
 	// Check if item type matches
-	if item.Type != itemType {
+	if item.Type != entry.itemType {
 		result.Skipped = true
-		result.Reason = fmt.Sprintf("is a %s, not a %s", item.Type, itemType)
+		result.Reason = fmt.Sprintf("is a %s, not a %s", item.Type, entry.itemType)
 
 		return result
 	}
@@ -427,6 +1010,35 @@ func (m *Manager) installProjectItem(
 		return result
 	}
 
+	// Check that everything it requires was installed, so nothing is installed without its requirements
+	for _, req := range entry.requires {
+		reqResult := done[req]
+		if reqResult.Success {
+			continue
+		}
+
+		if reqResult.Error != nil {
+			result.Error = fmt.Errorf("%w: %s requires %s, which failed for %s", ErrRequirementFailed, item.Name, req, tool)
+			result.Reason = "requirement failed"
+		} else {
+			result.Skipped = true
+			result.Reason = fmt.Sprintf("requires %s, which is not available for %s", req, tool)
+		}
+
+		return result
+	}
+
+	// Verify against the lock in frozen mode
+	if lock != nil {
+		err := verifyLocked(item, tool, lock)
+		if err != nil {
+			result.Error = err
+			result.Reason = "does not match lock file"
+
+			return result
+		}
+	}
+
 	// Get install path
 	path, err := installer.GetInstallPath(*item, tool, scope)
 	if err != nil {
@@ -439,6 +1051,8 @@ func (m *Manager) installProjectItem(
 
 	// Check current state
 	state, _, _ := installer.GetItemState(*item, tool, scope)
+	result.State = state
+
 	if state == installer.StateUpToDate && !force {
 		result.Skipped = true
 		result.Success = true
@@ -457,6 +1071,10 @@ func (m *Manager) installProjectItem(
 
 	result.Success = installResult.Success
 
+	if installResult.Success {
+		result.State = installer.StateUpToDate
+	}
+
 	return result
 }
 
@@ -464,17 +1082,16 @@ func (m *Manager) installProjectItem(
 func (m *Manager) getTargetTools(projectCfg *project.Config) []registry.Tool {
 	if len(projectCfg.Tools) == 0 {
 		// No tools specified, use all supported tools
-		return []registry.Tool{registry.ToolOpenCode, registry.ToolClaude}
+		return registry.AllTools()
 	}
 
 	tools := make([]registry.Tool, 0, len(projectCfg.Tools))
 
 	for _, t := range projectCfg.Tools {
-		switch strings.ToLower(t) {
-		case "opencode":
-			tools = append(tools, registry.ToolOpenCode)
-		case "claude":
-			tools = append(tools, registry.ToolClaude)
+		for _, tool := range registry.AllTools() {
+			if strings.EqualFold(t, string(tool)) {
+				tools = append(tools, tool)
+			}
 		}
 	}
 
@@ -490,19 +1107,18 @@ func (m *Manager) GetProjectStatus(
 
 	tools := m.getTargetTools(projectCfg)
 
-	// Check skills
-	for _, skillName := range projectCfg.Skills {
-		for _, tool := range tools {
-			result := m.getItemStatus(skillName, registry.ItemTypeSkill, tool, scope)
-			results = append(results, result)
+	// Load the install records once per tool; without them, items are reported without provenance
+	metas := make(map[registry.Tool]*installer.Metadata, len(tools))
+
+	for _, tool := range tools {
+		if meta, err := installer.LoadMetadata(tool, scope); err == nil {
+			metas[tool] = meta
 		}
 	}
 
-	// Check agents
-	for _, agentName := range projectCfg.Agents {
+	for _, entry := range m.planProjectItems(projectCfg) {
 		for _, tool := range tools {
-			result := m.getItemStatus(agentName, registry.ItemTypeAgent, tool, scope)
-			results = append(results, result)
+			results = append(results, m.getItemStatus(entry, tool, scope, metas[tool]))
 		}
 	}
 
@@ -510,29 +1126,31 @@ func (m *Manager) GetProjectStatus(
 }
 
 // getItemStatus returns the installation status for a single item.
+// meta holds the install records of the tool, nil if they couldn't be loaded.
 func (m *Manager) getItemStatus(
-	name string,
-	itemType registry.ItemType,
+	entry projectEntry,
 	tool registry.Tool,
 	scope config.Scope,
+	meta *installer.Metadata,
 ) ProjectInstallResult {
 	result := ProjectInstallResult{
-		ItemName: name,
-		ItemType: itemType,
+		ItemName: entry.name,
+		ItemType: entry.itemType,
 		Tool:     tool,
 	}
 
-	item, err := m.GetItem(name)
-	if err != nil {
-		result.Error = err
-		result.Reason = "not found in registry"
+	if entry.err != nil {
+		result.Error = entry.err
+		result.Reason = resolveReason(entry.err)
 
 		return result
 	}
 
-	if item.Type != itemType {
+	item := entry.item
+
+	if item.Type != entry.itemType {
 		result.Skipped = true
-		result.Reason = fmt.Sprintf("is a %s, not a %s", item.Type, itemType)
+		result.Reason = fmt.Sprintf("is a %s, not a %s", item.Type, entry.itemType)
 
 		return result
 	}
@@ -554,6 +1172,13 @@ func (m *Manager) getItemStatus(
 	result.Path = path
 
 	state, _, _ := installer.GetItemState(*item, tool, scope)
+	result.State = state
+
+	if state.IsInstalled() && meta != nil {
+		if installed, ok := meta.Get(item.Name); ok {
+			result.Installed = &installed
+		}
+	}
 
 	switch state {
 	case installer.StateUpToDate:
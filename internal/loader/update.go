@@ -0,0 +1,69 @@
+package loader
+
+import (
+	"slices"
+
+	"github.com/monke/skillsmith/internal/config"
+	"github.com/monke/skillsmith/internal/installer"
+	"github.com/monke/skillsmith/internal/registry"
+)
+
+// UpdateResult is the outcome of updating an installed item for one tool.
+type UpdateResult struct {
+	ItemName  string
+	ItemType  registry.ItemType
+	Tool      registry.Tool
+	State     installer.ItemState // state before the update
+	Path      string
+	Merged    bool // local changes were merged into the update
+	Conflicts int  // conflicts the merge left in the file
+	Skipped   bool // locally modified and not merged
+	Error     error
+}
+
+// UpdateInstalled updates installed items that have an update available.
+// If names is empty, all installed items are considered. Locally modified items
+// are skipped unless merge is set, in which case the update is merged with the
+// local changes (see installer.Merge).
+func (m *Manager) UpdateInstalled(
+	names []string, tools []registry.Tool, scope config.Scope, merge bool,
+) []UpdateResult {
+	var results []UpdateResult
+
+	for _, tool := range tools {
+		m.migrateLegacyAgents(tool, scope)
+
+		for _, item := range m.ListItemsWithState(tool, scope, "") {
+			if !item.State.HasUpdate() || (len(names) > 0 && !slices.Contains(names, item.Item.Name)) {
+				continue
+			}
+
+			result := UpdateResult{
+				ItemName: item.Item.Name,
+				ItemType: item.Item.Type,
+				Tool:     tool,
+				State:    item.State,
+				Path:     item.InstallPath,
+			}
+
+			switch {
+			case !item.State.IsModified():
+				_, _, result.Error = m.Install(item.Item.Name, tool, scope, true)
+			case merge:
+				var merged *installer.Result
+
+				merged, _, result.Error = m.Merge(item.Item.Name, tool, scope)
+				if result.Error == nil {
+					result.Merged = true
+					result.Conflicts = merged.Conflicts
+				}
+			default:
+				result.Skipped = true
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
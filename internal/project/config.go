@@ -16,7 +16,7 @@ const ConfigFileName = ".skillsmith.yaml"
 type Config struct {
 	// Tools limits installation to specific tools.
 	// If empty, skills are installed for ALL compatible tools.
-	// Valid values: "claude", "opencode"
+	// Valid values: "claude", "opencode", "cursor", "copilot", or a tool declared in config.yaml
 	Tools []string `yaml:"tools,omitempty"`
 
 	// Registries are project-specific registry sources.
@@ -24,11 +24,21 @@ type Config struct {
 	// taking precedence (last wins for duplicate skill names).
 	Registries []config.RegistrySource `yaml:"registries,omitempty"`
 
-	// Skills lists the skills to install for this project.
+	// Skills lists the skills to install for this project, as [registry/]name[@ref]
+	// references (see ItemRef).
 	Skills []string `yaml:"skills,omitempty"`
 
-	// Agents lists the agents to install for this project.
+	// Agents lists the agents to install for this project, as [registry/]name[@ref]
+	// references (see ItemRef).
 	Agents []string `yaml:"agents,omitempty"`
+
+	// Bundles lists bundles to install for this project, as [registry/]name[@ref]
+	// references. Each bundle installs the skills and agents it requires.
+	Bundles []string `yaml:"bundles,omitempty"`
+
+	// Vars sets template variables for items that declare them,
+	// overriding values from config.yaml.
+	Vars map[string]string `yaml:"vars,omitempty"`
 }
 
 // HasTool returns true if the config includes the specified tool,
@@ -41,88 +51,146 @@ func (c *Config) HasTool(tool string) bool {
 	return slices.Contains(c.Tools, tool)
 }
 
-// AllItems returns all skills and agents combined.
+// AllItems returns all skills, agents and bundles combined.
 func (c *Config) AllItems() []string {
-	items := make([]string, 0, len(c.Skills)+len(c.Agents))
+	items := make([]string, 0, len(c.Skills)+len(c.Agents)+len(c.Bundles))
 	items = append(items, c.Skills...)
 	items = append(items, c.Agents...)
+	items = append(items, c.Bundles...)
 
 	return items
 }
 
-// IsEmpty returns true if the config has no skills or agents defined.
+// IsEmpty returns true if the config has no skills, agents or bundles defined.
 func (c *Config) IsEmpty() bool {
-	return len(c.Skills) == 0 && len(c.Agents) == 0
+	return len(c.Skills) == 0 && len(c.Agents) == 0 && len(c.Bundles) == 0
 }
 
-// AddSkill adds a skill to the config if not already present.
-// Returns true if the skill was added, false if it already existed.
-func (c *Config) AddSkill(name string) bool {
-	if slices.Contains(c.Skills, name) {
-		return false
-	}
+// AddSkill adds a skill reference to the config. An existing entry for the same skill,
+// such as "writing-go" when adding "team/writing-go@v1", is replaced.
+// Returns the replaced entry, if any, and false if the reference was already present.
+func (c *Config) AddSkill(ref string) (string, bool) {
+	var (
+		replaced string
+		added    bool
+	)
 
-	c.Skills = append(c.Skills, name)
+	c.Skills, replaced, added = addRef(c.Skills, ref)
 
-	return true
+	return replaced, added
 }
 
-// RemoveSkill removes a skill from the config.
+// RemoveSkill removes a skill from the config, given its name or any reference to it.
 // Returns true if the skill was removed, false if it wasn't present.
-func (c *Config) RemoveSkill(name string) bool {
-	for i, s := range c.Skills {
-		if s == name {
-			c.Skills = append(c.Skills[:i], c.Skills[i+1:]...)
-			return true
-		}
-	}
+func (c *Config) RemoveSkill(ref string) bool {
+	var removed bool
 
-	return false
+	c.Skills, removed = removeRef(c.Skills, ref)
+
+	return removed
 }
 
-// AddAgent adds an agent to the config if not already present.
-// Returns true if the agent was added, false if it already existed.
-func (c *Config) AddAgent(name string) bool {
-	if slices.Contains(c.Agents, name) {
-		return false
-	}
+// AddAgent adds an agent reference to the config, replacing an existing entry for the same agent.
+// Returns the replaced entry, if any, and false if the reference was already present.
+func (c *Config) AddAgent(ref string) (string, bool) {
+	var (
+		replaced string
+		added    bool
+	)
 
-	c.Agents = append(c.Agents, name)
+	c.Agents, replaced, added = addRef(c.Agents, ref)
 
-	return true
+	return replaced, added
 }
 
-// RemoveAgent removes an agent from the config.
+// RemoveAgent removes an agent from the config, given its name or any reference to it.
 // Returns true if the agent was removed, false if it wasn't present.
-func (c *Config) RemoveAgent(name string) bool {
-	for i, a := range c.Agents {
-		if a == name {
-			c.Agents = append(c.Agents[:i], c.Agents[i+1:]...)
-			return true
-		}
-	}
+func (c *Config) RemoveAgent(ref string) bool {
+	var removed bool
+
+	c.Agents, removed = removeRef(c.Agents, ref)
+
+	return removed
+}
+
+// AddBundle adds a bundle reference to the config, replacing an existing entry for the same bundle.
+// Returns the replaced entry, if any, and false if the reference was already present.
+func (c *Config) AddBundle(ref string) (string, bool) {
+	var (
+		replaced string
+		added    bool
+	)
+
+	c.Bundles, replaced, added = addRef(c.Bundles, ref)
+
+	return replaced, added
+}
 
-	return false
+// RemoveBundle removes a bundle from the config, given its name or any reference to it.
+// Returns true if the bundle was removed, false if it wasn't present.
+func (c *Config) RemoveBundle(ref string) bool {
+	var removed bool
+
+	c.Bundles, removed = removeRef(c.Bundles, ref)
+
+	return removed
+}
+
+// HasSkill returns true if the skill is in the config, under any reference.
+func (c *Config) HasSkill(ref string) bool {
+	return slices.ContainsFunc(c.Skills, sameItem(ref))
+}
+
+// HasAgent returns true if the agent is in the config, under any reference.
+func (c *Config) HasAgent(ref string) bool {
+	return slices.ContainsFunc(c.Agents, sameItem(ref))
+}
+
+// HasBundle returns true if the bundle is in the config, under any reference.
+func (c *Config) HasBundle(ref string) bool {
+	return slices.ContainsFunc(c.Bundles, sameItem(ref))
 }
 
-// HasSkill returns true if the skill is in the config.
-func (c *Config) HasSkill(name string) bool {
-	for _, s := range c.Skills {
-		if s == name {
-			return true
-		}
+// refName returns the item name of a reference. Malformed references are compared as written.
+func refName(ref string) string {
+	parsed, err := ParseRef(ref)
+	if err != nil {
+		return ref
 	}
 
-	return false
+	return parsed.Name
 }
 
-// HasAgent returns true if the agent is in the config.
-func (c *Config) HasAgent(name string) bool {
-	for _, a := range c.Agents {
-		if a == name {
-			return true
-		}
+// sameItem returns a function reporting whether a reference is to the same item as ref.
+func sameItem(ref string) func(string) bool {
+	name := refName(ref)
+
+	return func(other string) bool {
+		return refName(other) == name
 	}
+}
+
+// addRef adds ref to refs, replacing the entry for the same item if there is one.
+// It returns the new list, the replaced entry and whether the list changed.
+func addRef(refs []string, ref string) ([]string, string, bool) {
+	i := slices.IndexFunc(refs, sameItem(ref))
+
+	switch {
+	case i < 0:
+		return append(refs, ref), "", true
+	case refs[i] == ref:
+		return refs, "", false
+	default:
+		replaced := refs[i]
+		refs[i] = ref
+
+		return refs, replaced, true
+	}
+}
+
+// removeRef removes every entry for the same item as ref from refs.
+func removeRef(refs []string, ref string) ([]string, bool) {
+	remaining := slices.DeleteFunc(slices.Clone(refs), sameItem(ref))
 
-	return false
+	return remaining, len(remaining) < len(refs)
 }
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/monke/skillsmith/internal/fsutil"
 )
 
 // Common errors.
@@ -92,7 +94,7 @@ func Save(cfg *Config, dir string) error {
 		return fmt.Errorf("marshal config: %w", err)
 	}
 
-	err = os.WriteFile(configPath, data, filePermissions)
+	err = fsutil.WriteFile(configPath, data, filePermissions)
 	if err != nil {
 		return fmt.Errorf("write config: %w", err)
 	}
@@ -113,18 +115,35 @@ func SaveWithHeader(cfg *Config, dir string) error {
 # https://github.com/monke/skillsmith
 #
 # Limit to specific tools (optional):
-#   tools: [claude, opencode]
+#   tools: [claude, opencode, cursor, copilot]
 #
 # Add project-specific registries (optional):
 #   registries:
 #     - name: team-skills
 #       url: https://github.com/team/skills.git
+#       ref: v1.2.0  # optional tag, branch or commit SHA
+#
+# Skills and agents are [registry/]name[@ref] references:
+#   skills:
+#     - writing-go              # whichever registry wins
+#     - team-skills/committing  # from a specific registry
+#     - team-skills/releasing@v1.2.0
+#
+# Items are installed with everything they list under requires:, and
+# bundles install a named set of skills and agents:
+#   bundles:
+#     - team-skills/go-backend
+#
+# Set template variables used by items (optional):
+#   vars:
+#     ticket_prefix: PROJ
+#     default_branch: main
 
 `
 
 	content := header + string(data)
 
-	err = os.WriteFile(configPath, []byte(content), filePermissions)
+	err = fsutil.WriteFile(configPath, []byte(content), filePermissions)
 	if err != nil {
 		return fmt.Errorf("write config: %w", err)
 	}
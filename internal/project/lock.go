@@ -0,0 +1,141 @@
+package project
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/monke/skillsmith/internal/fsutil"
+	"github.com/monke/skillsmith/internal/registry"
+)
+
+// LockFileName is the name of the project lock file.
+const LockFileName = ".skillsmith.lock"
+
+// lockVersion is the current lock file format version.
+const lockVersion = 1
+
+// ErrLockNotFound is returned when a project has no lock file.
+var ErrLockNotFound = errors.New("project lock file not found")
+
+// Lock records exactly which content was installed for a project,
+// so that later installs can be verified against it.
+type Lock struct {
+	// Version is the lock file format version.
+	Version int `yaml:"version"`
+
+	// Items lists every locked item/tool combination.
+	Items []LockedItem `yaml:"items"`
+}
+
+// LockedItem records the resolved origin and content of a single item for a tool.
+type LockedItem struct {
+	// Name is the item name.
+	Name string `yaml:"name"`
+
+	// Type is the item type (agent or skill).
+	Type registry.ItemType `yaml:"type"`
+
+	// Tool is the tool the content was generated for.
+	Tool registry.Tool `yaml:"tool"`
+
+	// Source is the registry the item was resolved from.
+	Source string `yaml:"source"`
+
+	// Commit is the Git commit of the source (Git sources only).
+	Commit string `yaml:"commit,omitempty"`
+
+	// Hash is the hash of the installed content.
+	Hash string `yaml:"hash"`
+}
+
+// NewLock creates an empty lock.
+func NewLock() *Lock {
+	return &Lock{
+		Version: lockVersion,
+		Items:   []LockedItem{},
+	}
+}
+
+// Get returns the locked entry for an item and tool if it exists.
+func (l *Lock) Get(name string, tool registry.Tool) (LockedItem, bool) {
+	for _, item := range l.Items {
+		if item.Name == name && item.Tool == tool {
+			return item, true
+		}
+	}
+
+	return LockedItem{}, false
+}
+
+// Set stores a locked entry, replacing any existing entry for the same item and tool.
+// Entries are kept sorted so the lock file is deterministic.
+func (l *Lock) Set(item LockedItem) {
+	for i := range l.Items {
+		if l.Items[i].Name == item.Name && l.Items[i].Tool == item.Tool {
+			l.Items[i] = item
+
+			return
+		}
+	}
+
+	l.Items = append(l.Items, item)
+
+	slices.SortFunc(l.Items, func(a, b LockedItem) int {
+		return cmp.Or(
+			cmp.Compare(a.Type, b.Type),
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.Tool, b.Tool),
+		)
+	})
+}
+
+// GetLockPath returns the path to the lock file in the given directory.
+func GetLockPath(dir string) string {
+	return filepath.Join(dir, LockFileName)
+}
+
+// LoadLock loads the lock file from the given project directory.
+func LoadLock(dir string) (*Lock, error) {
+	lockPath := GetLockPath(dir)
+
+	data, err := os.ReadFile(lockPath) //nolint:gosec // path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrLockNotFound
+		}
+
+		return nil, fmt.Errorf("read lock: %w", err)
+	}
+
+	var lock Lock
+
+	err = yaml.Unmarshal(data, &lock)
+	if err != nil {
+		return nil, fmt.Errorf("parse lock: %w", err)
+	}
+
+	return &lock, nil
+}
+
+// SaveLock writes the lock file to the given project directory.
+func SaveLock(lock *Lock, dir string) error {
+	data, err := yaml.Marshal(lock)
+	if err != nil {
+		return fmt.Errorf("marshal lock: %w", err)
+	}
+
+	header := "# This file is generated by skillsmith. Do not edit.\n\n"
+
+	err = fsutil.WriteFile(GetLockPath(dir), []byte(header+string(data)), filePermissions)
+	if err != nil {
+		return fmt.Errorf("write lock: %w", err)
+	}
+
+	return nil
+}
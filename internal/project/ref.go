@@ -0,0 +1,70 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/monke/skillsmith/internal/registry"
+)
+
+// ErrInvalidRef is returned for malformed item references.
+var ErrInvalidRef = errors.New("invalid item reference")
+
+// ItemRef is a reference to a skill or agent in the project config,
+// written as [registry/]name[@ref]:
+//
+//	writing-go              the winning definition across all registries
+//	team/writing-go         the definition from the "team" registry
+//	team/writing-go@v1.2.0  ... which must be loaded at ref v1.2.0 or a commit starting with it
+type ItemRef struct {
+	Registry string // empty means any registry
+	Name     string
+	Ref      string // tag, branch or commit SHA prefix; empty means any
+}
+
+// ParseRef parses an item reference.
+func ParseRef(s string) (ItemRef, error) {
+	var ref ItemRef
+
+	rest := s
+
+	if idx := strings.LastIndex(rest, "@"); idx >= 0 {
+		ref.Ref = rest[idx+1:]
+		rest = rest[:idx]
+
+		if ref.Ref == "" {
+			return ItemRef{}, fmt.Errorf("%w %q: empty ref after @", ErrInvalidRef, s)
+		}
+	}
+
+	ref.Registry, ref.Name = registry.SplitRef(rest)
+
+	if ref.Name == "" {
+		return ItemRef{}, fmt.Errorf("%w %q: missing name", ErrInvalidRef, s)
+	}
+
+	if strings.Contains(rest, "/") && ref.Registry == "" {
+		return ItemRef{}, fmt.Errorf("%w %q: empty registry before /", ErrInvalidRef, s)
+	}
+
+	return ref, nil
+}
+
+// Qualified returns the reference without the ref constraint, as understood by registry lookups.
+func (r ItemRef) Qualified() string {
+	if r.Registry == "" {
+		return r.Name
+	}
+
+	return r.Registry + "/" + r.Name
+}
+
+// String formats the reference as [registry/]name[@ref].
+func (r ItemRef) String() string {
+	if r.Ref == "" {
+		return r.Qualified()
+	}
+
+	return r.Qualified() + "@" + r.Ref
+}
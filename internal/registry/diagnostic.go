@@ -0,0 +1,89 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var errInvalidFrontmatter = errors.New("invalid frontmatter")
+
+// yamlLinePattern matches the line reference in yaml.v3 error messages.
+var yamlLinePattern = regexp.MustCompile(`line (\d+): `)
+
+// unknownFieldPattern matches the yaml.v3 error for unknown keys in strict mode.
+var unknownFieldPattern = regexp.MustCompile(`^field (\S+) not found in type \S+$`)
+
+// Diagnostic describes a file that was skipped while loading a source.
+type Diagnostic struct {
+	Path   string // path of the file, relative to the source root
+	Line   int    // 1-based line number, or 0 if unknown
+	Reason string
+}
+
+// String formats the diagnostic as path:line: reason.
+func (d Diagnostic) String() string {
+	if d.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", d.Path, d.Line, d.Reason)
+	}
+
+	return fmt.Sprintf("%s: %s", d.Path, d.Reason)
+}
+
+// DiagnosticSource is implemented by sources that report files skipped during the last Load.
+type DiagnosticSource interface {
+	Diagnostics() []Diagnostic
+}
+
+// ParseError is an error in a markdown file with the line it occurred on.
+type ParseError struct {
+	Line int // 1-based line number in the file
+	Err  error
+}
+
+// Error implements error.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// newDiagnostic creates a diagnostic for a file that failed to load.
+func newDiagnostic(path string, err error) Diagnostic {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return Diagnostic{Path: path, Line: parseErr.Line, Reason: parseErr.Err.Error()}
+	}
+
+	return Diagnostic{Path: path, Reason: err.Error()}
+}
+
+// frontmatterError converts a YAML error into a ParseError.
+// startLine is the file line of the first frontmatter line.
+func frontmatterError(err error, startLine int) *ParseError {
+	msg := err.Error()
+
+	loc := yamlLinePattern.FindStringSubmatchIndex(msg)
+	if loc == nil {
+		return &ParseError{Line: startLine, Err: fmt.Errorf("%w: %s", errInvalidFrontmatter, msg)}
+	}
+
+	yamlLine, _ := strconv.Atoi(msg[loc[2]:loc[3]])
+
+	// Keep the reason of the first error only
+	reason, _, _ := strings.Cut(msg[loc[1]:], "\n")
+
+	if m := unknownFieldPattern.FindStringSubmatch(reason); m != nil {
+		reason = fmt.Sprintf("unknown key %q", m[1])
+	}
+
+	return &ParseError{
+		Line: startLine + yamlLine - 1,
+		Err:  fmt.Errorf("%w: %s", errInvalidFrontmatter, reason),
+	}
+}
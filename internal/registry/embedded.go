@@ -2,7 +2,8 @@ package registry
 
 // EmbeddedSource is a Source backed by the embedded registry.
 type EmbeddedSource struct {
-	name string
+	name        string
+	diagnostics []Diagnostic
 }
 
 // NewEmbeddedSource creates a new embedded source.
@@ -29,6 +30,8 @@ func (s *EmbeddedSource) Load() ([]Item, error) {
 		return nil, err
 	}
 
+	s.diagnostics = reg.Diagnostics
+
 	// Tag all items with this source
 	for i := range reg.Items {
 		reg.Items[i].Source = s.name
@@ -36,3 +39,8 @@ func (s *EmbeddedSource) Load() ([]Item, error) {
 
 	return reg.Items, nil
 }
+
+// Diagnostics returns the files skipped during the last Load.
+func (s *EmbeddedSource) Diagnostics() []Diagnostic {
+	return s.diagnostics
+}
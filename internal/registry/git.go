@@ -1,28 +1,48 @@
 package registry
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 )
 
+var errRefNotFound = errors.New("ref not found in repository")
+
+// ErrNotCached is returned in offline mode for a repository that was never fetched.
+var ErrNotCached = errors.New("registry has never been fetched")
+
+// fetchStampFile records the time of the last successful fetch, relative to the .git dir.
+const fetchStampFile = "skillsmith-fetched"
+
 // GitSource is a Source backed by a Git repository.
 // The repository is cloned to a local cache directory.
+// If a ref is set, the cache is checked out at exactly that tag, branch or commit.
 type GitSource struct {
 	name     string
 	url      string
-	cacheDir string // computed from URL hash
+	ref      string        // tag, branch or commit SHA; empty means default branch
+	cacheDir string        // computed from URL hash
+	ttl      time.Duration // fetch only if the last fetch is older; zero always fetches
+	offline  bool          // never touch the network, use the cache only
+
+	diagnostics []Diagnostic
 }
 
 // NewGitSource creates a new Git repository source.
-func NewGitSource(name, url string) *GitSource {
+// The ref may be a tag, branch or commit SHA; an empty ref tracks the default branch.
+func NewGitSource(name, url, ref string) *GitSource {
 	return &GitSource{
 		name:     name,
 		url:      url,
+		ref:      ref,
 		cacheDir: "", // computed lazily
 	}
 }
@@ -37,9 +57,31 @@ func (s *GitSource) URL() string {
 	return s.url
 }
 
+// Ref returns the pinned ref, or an empty string if the default branch is tracked.
+func (s *GitSource) Ref() string {
+	return s.ref
+}
+
+// SetTTL sets how long a fetched repository is considered fresh.
+// Loading within the TTL of the last fetch uses the cache without fetching.
+func (s *GitSource) SetTTL(ttl time.Duration) {
+	s.ttl = ttl
+}
+
+// SetOffline makes the source load from the cache only, without fetching.
+func (s *GitSource) SetOffline(offline bool) {
+	s.offline = offline
+}
+
 // Load loads all items from the Git repository.
 // The repository is cloned/updated in the cache directory.
 func (s *GitSource) Load() ([]Item, error) {
+	unlock, err := s.lockCache()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	cacheDir, err := s.ensureCached()
 	if err != nil {
 		return nil, fmt.Errorf("ensure cached: %w", err)
@@ -53,14 +95,27 @@ func (s *GitSource) Load() ([]Item, error) {
 		return nil, fmt.Errorf("load from cache: %w", err)
 	}
 
-	// Tag all items with this source
+	s.diagnostics = reg.Diagnostics
+
+	commit, err := s.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	// Tag all items with this source and the commit they were loaded from
 	for i := range reg.Items {
 		reg.Items[i].Source = s.name
+		reg.Items[i].Commit = commit
 	}
 
 	return reg.Items, nil
 }
 
+// Diagnostics returns the files skipped during the last Load.
+func (s *GitSource) Diagnostics() []Diagnostic {
+	return s.diagnostics
+}
+
 // CacheDir returns the cache directory for this source.
 func (s *GitSource) CacheDir() (string, error) {
 	if s.cacheDir != "" {
@@ -72,8 +127,14 @@ func (s *GitSource) CacheDir() (string, error) {
 		return "", err
 	}
 
-	// Create a hash of the URL for the directory name
-	hash := sha256.Sum256([]byte(s.url))
+	// Create a hash of the URL (and ref, if pinned) for the directory name.
+	// Pinned sources get their own cache so they never share a shallow clone.
+	key := s.url
+	if s.ref != "" {
+		key += "#" + s.ref
+	}
+
+	hash := sha256.Sum256([]byte(key))
 	hashStr := hex.EncodeToString(hash[:8]) // use first 8 bytes
 
 	// Create a safe directory name: name-hash
@@ -83,7 +144,41 @@ func (s *GitSource) CacheDir() (string, error) {
 	return s.cacheDir, nil
 }
 
+// Commit returns the commit SHA currently checked out in the cache.
+func (s *GitSource) Commit() (string, error) {
+	cacheDir, err := s.CacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	commit, err := runGit(cacheDir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("resolve commit: %w", err)
+	}
+
+	return commit, nil
+}
+
+// cacheLocks serializes access to cache directories, since several sources
+// may share a repository and sources are loaded concurrently.
+var cacheLocks sync.Map // cache dir -> *sync.Mutex
+
+// lockCache locks the cache directory of this source and returns the unlock function.
+func (s *GitSource) lockCache() (func(), error) {
+	cacheDir, err := s.CacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	value, _ := cacheLocks.LoadOrStore(cacheDir, &sync.Mutex{})
+	mu, _ := value.(*sync.Mutex)
+	mu.Lock()
+
+	return mu.Unlock, nil
+}
+
 // ensureCached ensures the repository is cloned and up-to-date.
+// Fetching is skipped in offline mode and while the last fetch is within the TTL.
 func (s *GitSource) ensureCached() (string, error) {
 	cacheDir, err := s.CacheDir()
 	if err != nil {
@@ -92,17 +187,24 @@ func (s *GitSource) ensureCached() (string, error) {
 
 	// Check if cache directory exists
 	if dirExists(cacheDir) {
-		// Repository already cloned, try to pull latest
+		if s.offline || s.isFresh() {
+			return cacheDir, nil
+		}
+
+		// Repository already cloned, pull latest.
+		// Failing loudly is better than silently serving stale content.
 		err = s.pull(cacheDir)
 		if err != nil {
-			// Pull failed, but we can still use existing cache
-			// This handles offline scenarios gracefully
-			return cacheDir, nil
+			return "", fmt.Errorf("update: %w", err)
 		}
 
 		return cacheDir, nil
 	}
 
+	if s.offline {
+		return "", fmt.Errorf("%w, run 'skillsmith registry update' while online", ErrNotCached)
+	}
+
 	// Clone the repository
 	err = s.clone(cacheDir)
 	if err != nil {
@@ -112,6 +214,57 @@ func (s *GitSource) ensureCached() (string, error) {
 	return cacheDir, nil
 }
 
+// isFresh returns true if the last fetch happened within the TTL.
+func (s *GitSource) isFresh() bool {
+	if s.ttl <= 0 {
+		return false
+	}
+
+	fetched, err := s.LastFetched()
+	if err != nil || fetched.IsZero() {
+		return false
+	}
+
+	return time.Since(fetched) < s.ttl
+}
+
+// LastFetched returns the time of the last successful fetch.
+// Returns the zero time if the repository was never fetched.
+func (s *GitSource) LastFetched() (time.Time, error) {
+	cacheDir, err := s.CacheDir()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(cacheDir, ".git", fetchStampFile)) //nolint:gosec // path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return time.Time{}, nil
+		}
+
+		return time.Time{}, fmt.Errorf("read fetch time: %w", err)
+	}
+
+	fetched, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse fetch time: %w", err)
+	}
+
+	return fetched, nil
+}
+
+// markFetched records the current time as the last successful fetch.
+func (s *GitSource) markFetched(dir string) error {
+	stamp := time.Now().UTC().Format(time.RFC3339) + "\n"
+
+	err := os.WriteFile(filepath.Join(dir, ".git", fetchStampFile), []byte(stamp), 0o600)
+	if err != nil {
+		return fmt.Errorf("write fetch time: %w", err)
+	}
+
+	return nil
+}
+
 // clone clones the repository to the specified directory.
 func (s *GitSource) clone(dir string) error {
 	// Ensure parent directory exists
@@ -120,36 +273,105 @@ func (s *GitSource) clone(dir string) error {
 		return fmt.Errorf("create cache dir: %w", err)
 	}
 
-	// Clone with depth 1 for faster cloning
-	cmd := exec.Command("git", "clone", "--depth", "1", s.url, dir)
-	cmd.Stdout = nil // suppress output
-	cmd.Stderr = nil
+	if s.ref == "" {
+		// Clone with depth 1 for faster cloning
+		_, err = runGit("", "clone", "--quiet", "--depth", "1", s.url, dir)
+		if err != nil {
+			return fmt.Errorf("git clone failed: %w", err)
+		}
+
+		return s.markFetched(dir)
+	}
 
-	err = cmd.Run()
+	// Pinned refs may point at any commit, so a full clone is required
+	_, err = runGit("", "clone", "--quiet", "--no-checkout", s.url, dir)
 	if err != nil {
 		return fmt.Errorf("git clone failed: %w", err)
 	}
 
-	return nil
+	err = s.checkout(dir)
+	if err != nil {
+		// Don't leave a cache without a working tree behind
+		_ = os.RemoveAll(dir)
+
+		return err
+	}
+
+	return s.markFetched(dir)
 }
 
 // pull updates the repository.
+// For pinned sources this fetches from origin and re-checks out the ref.
 func (s *GitSource) pull(dir string) error {
-	cmd := exec.Command("git", "pull", "--ff-only")
-	cmd.Dir = dir
-	cmd.Stdout = nil
-	cmd.Stderr = nil
+	if s.ref == "" {
+		_, err := runGit(dir, "pull", "--quiet", "--ff-only")
+		if err != nil {
+			return fmt.Errorf("git pull failed: %w", err)
+		}
+
+		return s.markFetched(dir)
+	}
+
+	_, err := runGit(dir, "fetch", "--quiet", "--tags", "--force", "origin")
+	if err != nil {
+		return fmt.Errorf("git fetch failed: %w", err)
+	}
+
+	err = s.checkout(dir)
+	if err != nil {
+		return err
+	}
+
+	return s.markFetched(dir)
+}
+
+// checkout checks out the pinned ref in detached HEAD mode.
+func (s *GitSource) checkout(dir string) error {
+	commit, err := s.resolveRef(dir)
+	if err != nil {
+		return err
+	}
 
-	err := cmd.Run()
+	_, err = runGit(dir, "checkout", "--quiet", "--force", "--detach", commit)
 	if err != nil {
-		return fmt.Errorf("git pull failed: %w", err)
+		return fmt.Errorf("git checkout %s failed: %w", s.ref, err)
 	}
 
 	return nil
 }
 
+// resolveRef resolves the pinned ref to a commit SHA.
+// Branches are resolved against origin so that fetched updates are picked up.
+func (s *GitSource) resolveRef(dir string) (string, error) {
+	candidates := []string{
+		"refs/remotes/origin/" + s.ref,
+		"refs/tags/" + s.ref,
+		s.ref,
+	}
+
+	for _, candidate := range candidates {
+		commit, err := runGit(dir, "rev-parse", "--verify", "--quiet", candidate+"^{commit}")
+		if err == nil && commit != "" {
+			return commit, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", errRefNotFound, s.ref)
+}
+
 // Refresh forces a fresh pull of the repository.
 func (s *GitSource) Refresh() error {
+	unlock, err := s.lockCache()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return s.refresh()
+}
+
+// refresh pulls or clones the repository. The caller must hold the cache lock.
+func (s *GitSource) refresh() error {
 	cacheDir, err := s.CacheDir()
 	if err != nil {
 		return err
@@ -162,8 +384,50 @@ func (s *GitSource) Refresh() error {
 	return s.pull(cacheDir)
 }
 
+// Update fetches the repository and returns the commit before and after.
+// The old commit is empty if the repository was not cached yet.
+func (s *GitSource) Update() (string, string, error) {
+	unlock, err := s.lockCache()
+	if err != nil {
+		return "", "", err
+	}
+	defer unlock()
+
+	cacheDir, err := s.CacheDir()
+	if err != nil {
+		return "", "", err
+	}
+
+	var oldCommit string
+
+	if dirExists(cacheDir) {
+		oldCommit, err = s.Commit()
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	err = s.refresh()
+	if err != nil {
+		return oldCommit, "", err
+	}
+
+	newCommit, err := s.Commit()
+	if err != nil {
+		return oldCommit, "", err
+	}
+
+	return oldCommit, newCommit, nil
+}
+
 // Clear removes the cached repository.
 func (s *GitSource) Clear() error {
+	unlock, err := s.lockCache()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	cacheDir, err := s.CacheDir()
 	if err != nil {
 		return err
@@ -176,6 +440,45 @@ func (s *GitSource) Clear() error {
 	return nil
 }
 
+// runGit runs a git command in dir and returns its trimmed stdout.
+// On failure, git's stderr is included in the returned error.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+
+		return "", err //nolint:wrapcheck // wrapped by callers
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ClearAllCaches removes the cached repositories of all Git sources,
+// including caches of sources that are no longer configured.
+func ClearAllCaches() error {
+	baseDir, err := getCacheBaseDir()
+	if err != nil {
+		return err
+	}
+
+	err = os.RemoveAll(baseDir)
+	if err != nil {
+		return fmt.Errorf("remove cache dir: %w", err)
+	}
+
+	return nil
+}
+
 // getCacheBaseDir returns the base directory for caching git repositories.
 func getCacheBaseDir() (string, error) {
 	// Use XDG cache dir or fallback to ~/.cache
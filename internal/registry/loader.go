@@ -5,16 +5,22 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
-//go:embed content/agents/*.md content/skills/*.md
+//go:embed content/agents/*.md content/skills
 var embeddedFS embed.FS
 
+// skillFileName is the main file of a directory-form skill.
+const skillFileName = "SKILL.md"
+
 var (
 	errNoFrontmatter      = errors.New("file must start with YAML frontmatter (---)")
 	errNoClosingDelimiter = errors.New("could not find closing frontmatter delimiter (---)")
@@ -25,8 +31,21 @@ func Load() (*Registry, error) {
 	return LoadFromFS(embeddedFS, "content")
 }
 
+// LoadOptions controls how a registry is loaded.
+type LoadOptions struct {
+	// Strict rejects files with unknown frontmatter keys.
+	Strict bool
+}
+
 // LoadFromFS loads a registry from a filesystem (embedded or real).
+// Files that fail to parse are skipped and reported in the registry's Diagnostics,
+// so a single broken file doesn't hide the rest of the source.
 func LoadFromFS(fsys fs.FS, root string) (*Registry, error) {
+	return LoadFromFSWithOptions(fsys, root, LoadOptions{})
+}
+
+// LoadFromFSWithOptions loads a registry from a filesystem with the given options.
+func LoadFromFSWithOptions(fsys fs.FS, root string, opts LoadOptions) (*Registry, error) {
 	reg := &Registry{}
 
 	// Load agents
@@ -37,9 +56,11 @@ func LoadFromFS(fsys fs.FS, root string) (*Registry, error) {
 			return nil //nolint:nilerr // Skip on error or non-md files
 		}
 
-		item, parseErr := loadItemFromFS(fsys, path, ItemTypeAgent)
+		item, parseErr := loadItemFromFS(fsys, path, ItemTypeAgent, opts)
 		if parseErr != nil {
-			return fmt.Errorf("parse %s: %w", path, parseErr)
+			reg.Diagnostics = append(reg.Diagnostics, newDiagnostic(path, parseErr))
+
+			return nil
 		}
 
 		reg.Items = append(reg.Items, *item)
@@ -50,17 +71,63 @@ func LoadFromFS(fsys fs.FS, root string) (*Registry, error) {
 		return nil, fmt.Errorf("walk agents: %w", agentErr)
 	}
 
-	// Load skills
-	skillsDir := filepath.Join(root, "skills")
+	// Load bundles
+	bundlesDir := filepath.Join(root, "bundles")
 
-	skillErr := fs.WalkDir(fsys, skillsDir, func(path string, d fs.DirEntry, err error) error {
+	bundleErr := fs.WalkDir(fsys, bundlesDir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".md") {
 			return nil //nolint:nilerr // Skip on error or non-md files
 		}
 
-		item, parseErr := loadItemFromFS(fsys, path, ItemTypeSkill)
+		item, parseErr := loadItemFromFS(fsys, path, ItemTypeBundle, opts)
+		if parseErr != nil {
+			reg.Diagnostics = append(reg.Diagnostics, newDiagnostic(path, parseErr))
+
+			return nil
+		}
+
+		reg.Items = append(reg.Items, *item)
+
+		return nil
+	})
+	if bundleErr != nil {
+		return nil, fmt.Errorf("walk bundles: %w", bundleErr)
+	}
+
+	// Load skills, either as single files (skills/<name>.md) or
+	// directories with bundled resources (skills/<name>/SKILL.md)
+	skillsDir := filepath.Join(root, "skills")
+
+	skillErr := fs.WalkDir(fsys, skillsDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // Skip on error
+		}
+
+		if d.IsDir() {
+			if p == skillsDir || !fileExistsFS(fsys, path.Join(p, skillFileName)) {
+				return nil
+			}
+
+			item, parseErr := loadSkillDirFromFS(fsys, p, opts)
+			if parseErr != nil {
+				reg.Diagnostics = append(reg.Diagnostics, newDiagnostic(path.Join(p, skillFileName), parseErr))
+			} else {
+				reg.Items = append(reg.Items, *item)
+			}
+
+			// Other markdown files in the directory are resources, not skills
+			return fs.SkipDir
+		}
+
+		if !strings.HasSuffix(p, ".md") {
+			return nil
+		}
+
+		item, parseErr := loadItemFromFS(fsys, p, ItemTypeSkill, opts)
 		if parseErr != nil {
-			return fmt.Errorf("parse %s: %w", path, parseErr)
+			reg.Diagnostics = append(reg.Diagnostics, newDiagnostic(p, parseErr))
+
+			return nil
 		}
 
 		reg.Items = append(reg.Items, *item)
@@ -75,13 +142,13 @@ func LoadFromFS(fsys fs.FS, root string) (*Registry, error) {
 }
 
 // loadItemFromFS loads a single item from a markdown file.
-func loadItemFromFS(fsys fs.FS, path string, itemType ItemType) (*Item, error) {
+func loadItemFromFS(fsys fs.FS, path string, itemType ItemType, opts LoadOptions) (*Item, error) {
 	data, err := fs.ReadFile(fsys, path)
 	if err != nil {
 		return nil, fmt.Errorf("read file: %w", err)
 	}
 
-	item, err := ParseItem(data)
+	item, err := parseItem(data, opts.Strict)
 	if err != nil {
 		return nil, err
 	}
@@ -92,18 +159,85 @@ func loadItemFromFS(fsys fs.FS, path string, itemType ItemType) (*Item, error) {
 	return item, nil
 }
 
+// loadSkillDirFromFS loads a directory-form skill: SKILL.md plus every other file in the directory.
+// Hidden files are ignored.
+func loadSkillDirFromFS(fsys fs.FS, dir string, opts LoadOptions) (*Item, error) {
+	item, err := loadItemFromFS(fsys, path.Join(dir, skillFileName), ItemTypeSkill, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	err = fs.WalkDir(fsys, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if strings.HasPrefix(d.Name(), ".") && p != dir {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		rel := strings.TrimPrefix(p, dir+"/")
+		if d.IsDir() || rel == skillFileName {
+			return nil
+		}
+
+		content, readErr := fs.ReadFile(fsys, p)
+		if readErr != nil {
+			return fmt.Errorf("read %s: %w", rel, readErr)
+		}
+
+		info, infoErr := d.Info()
+		if infoErr != nil {
+			return fmt.Errorf("stat %s: %w", rel, infoErr)
+		}
+
+		item.Files = append(item.Files, File{Path: rel, Content: content, Mode: info.Mode().Perm()})
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk skill files: %w", err)
+	}
+
+	slices.SortFunc(item.Files, func(a, b File) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return item, nil
+}
+
+// fileExistsFS checks if a regular file exists in a filesystem.
+func fileExistsFS(fsys fs.FS, name string) bool {
+	info, err := fs.Stat(fsys, name)
+
+	return err == nil && !info.IsDir()
+}
+
 // ParseItem parses a markdown file with YAML frontmatter into an Item.
+// Syntax errors are returned as *ParseError with the offending line.
 func ParseItem(data []byte) (*Item, error) {
-	frontmatter, body, err := splitFrontmatter(data)
+	return parseItem(data, false)
+}
+
+// parseItem parses an item, rejecting unknown frontmatter keys if strict is set.
+func parseItem(data []byte, strict bool) (*Item, error) {
+	frontmatter, body, startLine, err := splitFrontmatter(data)
 	if err != nil {
-		return nil, err
+		return nil, &ParseError{Line: 1, Err: err}
 	}
 
 	var item Item
 
-	err = yaml.Unmarshal(frontmatter, &item)
-	if err != nil {
-		return nil, fmt.Errorf("parse frontmatter: %w", err)
+	dec := yaml.NewDecoder(bytes.NewReader(frontmatter))
+	dec.KnownFields(strict)
+
+	err = dec.Decode(&item)
+	if err != nil && !errors.Is(err, io.EOF) { // io.EOF means empty frontmatter
+		return nil, frontmatterError(err, startLine)
 	}
 
 	item.Body = strings.TrimSpace(body)
@@ -112,14 +246,15 @@ func ParseItem(data []byte) (*Item, error) {
 }
 
 // splitFrontmatter splits a markdown file into frontmatter and body.
-func splitFrontmatter(data []byte) ([]byte, string, error) {
+// It also returns the file line on which the frontmatter content starts.
+func splitFrontmatter(data []byte) ([]byte, string, int, error) {
 	const delimiter = "---"
 
 	content := string(data)
 
 	// Must start with ---
 	if !strings.HasPrefix(content, delimiter) {
-		return nil, "", errNoFrontmatter
+		return nil, "", 0, errNoFrontmatter
 	}
 
 	// Find the closing ---
@@ -127,11 +262,15 @@ func splitFrontmatter(data []byte) ([]byte, string, error) {
 
 	before, after, found := strings.Cut(rest, "\n"+delimiter)
 	if !found {
-		return nil, "", errNoClosingDelimiter
+		return nil, "", 0, errNoClosingDelimiter
 	}
 
 	// Remove leading newline from body
 	body := strings.TrimPrefix(after, "\n")
 
-	return bytes.TrimSpace([]byte(before)), body, nil
+	// The delimiter is on line 1; skipped blank lines push the start further down
+	leading := before[:len(before)-len(strings.TrimLeft(before, " \t\r\n"))]
+	startLine := 1 + strings.Count(leading, "\n")
+
+	return bytes.TrimSpace([]byte(before)), body, startLine, nil
 }
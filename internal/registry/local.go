@@ -13,8 +13,9 @@ var (
 
 // LocalSource is a Source backed by a local directory on disk.
 type LocalSource struct {
-	name string
-	path string
+	name        string
+	path        string
+	diagnostics []Diagnostic
 }
 
 // NewLocalSource creates a new local directory source.
@@ -54,6 +55,8 @@ func (s *LocalSource) Load() ([]Item, error) {
 		return nil, fmt.Errorf("load from %s: %w", s.path, err)
 	}
 
+	s.diagnostics = reg.Diagnostics
+
 	// Tag all items with this source
 	for i := range reg.Items {
 		reg.Items[i].Source = s.name
@@ -62,6 +65,11 @@ func (s *LocalSource) Load() ([]Item, error) {
 	return reg.Items, nil
 }
 
+// Diagnostics returns the files skipped during the last Load.
+func (s *LocalSource) Diagnostics() []Diagnostic {
+	return s.diagnostics
+}
+
 // Path returns the filesystem path of this source.
 func (s *LocalSource) Path() string {
 	return s.path
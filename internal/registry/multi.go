@@ -3,8 +3,22 @@ package registry
 import (
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 )
 
+// maxConcurrentLoads bounds how many sources are loaded at the same time.
+const maxConcurrentLoads = 4
+
+// SourceResult describes the outcome of loading a single source.
+type SourceResult struct {
+	Name        string
+	ItemCount   int
+	Duration    time.Duration
+	Err         error
+	Diagnostics []Diagnostic // files skipped while loading, if the source reports them
+}
+
 // MultiRegistry aggregates items from multiple sources.
 // Sources are loaded in order; last source wins for duplicate names.
 // This allows project-specific registries to override builtin/global items.
@@ -12,7 +26,9 @@ type MultiRegistry struct {
 	sources  []Source
 	items    []Item
 	itemsMap map[string]int // maps item name to index in items slice
+	shadowed []Item         // items replaced by a later source, in load order
 	errors   []error        // errors from sources that failed to load
+	results  []SourceResult // per-source outcome, in source order
 }
 
 // NewMultiRegistry creates a new multi-source registry.
@@ -31,25 +47,39 @@ func (m *MultiRegistry) AddSource(source Source) {
 }
 
 // Load loads items from all sources.
-// Last source wins for duplicate item names, allowing overrides.
+// Sources are loaded concurrently, but merged in order so the last source still wins
+// for duplicate item names, allowing overrides.
 // If a source fails to load, its error is recorded but other sources continue loading.
 func (m *MultiRegistry) Load() error {
 	m.items = make([]Item, 0)
 	m.itemsMap = make(map[string]int)
+	m.shadowed = make([]Item, 0)
 	m.errors = make([]error, 0)
+	m.results = make([]SourceResult, len(m.sources))
+
+	loaded := m.loadSources()
+
+	for i, source := range m.sources {
+		m.results[i] = SourceResult{
+			Name:        source.Name(),
+			ItemCount:   len(loaded[i].items),
+			Duration:    loaded[i].duration,
+			Err:         loaded[i].err,
+			Diagnostics: loaded[i].diagnostics,
+		}
 
-	for _, source := range m.sources {
-		items, err := source.Load()
-		if err != nil {
+		if loaded[i].err != nil {
 			// Record the error but continue with other sources
-			m.errors = append(m.errors, fmt.Errorf("source %s: %w", source.Name(), err))
+			m.errors = append(m.errors, fmt.Errorf("source %s: %w", source.Name(), loaded[i].err))
 
 			continue
 		}
 
-		for _, item := range items {
-			// Last source wins - replace existing item if present
+		for _, item := range loaded[i].items {
+			// Last source wins - replace existing item if present,
+			// but remember it so overrides can be explained
 			if idx, exists := m.itemsMap[item.Name]; exists {
+				m.shadowed = append(m.shadowed, m.items[idx])
 				m.items[idx] = item
 			} else {
 				m.itemsMap[item.Name] = len(m.items)
@@ -61,6 +91,51 @@ func (m *MultiRegistry) Load() error {
 	return nil
 }
 
+// sourceLoad holds the raw outcome of loading one source.
+type sourceLoad struct {
+	items       []Item
+	duration    time.Duration
+	err         error
+	diagnostics []Diagnostic
+}
+
+// loadSources loads all sources with a bounded worker pool.
+// The returned slice is indexed like m.sources.
+func (m *MultiRegistry) loadSources() []sourceLoad {
+	loaded := make([]sourceLoad, len(m.sources))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for range min(maxConcurrentLoads, len(m.sources)) {
+		wg.Go(func() {
+			for i := range jobs {
+				start := time.Now()
+				items, err := m.sources[i].Load()
+				loaded[i] = sourceLoad{items: items, duration: time.Since(start), err: err}
+
+				if ds, ok := m.sources[i].(DiagnosticSource); ok {
+					loaded[i].diagnostics = ds.Diagnostics()
+				}
+			}
+		})
+	}
+
+	for i := range m.sources {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return loaded
+}
+
+// Results returns the per-source load results in source order.
+func (m *MultiRegistry) Results() []SourceResult {
+	return m.results
+}
+
 // Errors returns any errors that occurred during loading.
 func (m *MultiRegistry) Errors() []error {
 	return m.errors
@@ -87,7 +162,7 @@ func (m *MultiRegistry) ErrorString() string {
 
 // Registry returns the aggregated registry.
 func (m *MultiRegistry) Registry() *Registry {
-	return &Registry{Items: m.items}
+	return &Registry{Items: m.items, Shadowed: m.shadowed}
 }
 
 // Sources returns the list of configured sources.
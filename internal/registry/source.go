@@ -15,9 +15,10 @@ type Source interface {
 
 // FSSource is a Source backed by a filesystem (embedded or real).
 type FSSource struct {
-	name string
-	fs   fs.FS
-	root string
+	name        string
+	fs          fs.FS
+	root        string
+	diagnostics []Diagnostic
 }
 
 // NewFSSource creates a new filesystem-backed source.
@@ -41,6 +42,8 @@ func (s *FSSource) Load() ([]Item, error) {
 		return nil, err
 	}
 
+	s.diagnostics = reg.Diagnostics
+
 	// Tag all items with this source
 	for i := range reg.Items {
 		reg.Items[i].Source = s.name
@@ -48,3 +51,8 @@ func (s *FSSource) Load() ([]Item, error) {
 
 	return reg.Items, nil
 }
+
+// Diagnostics returns the files skipped during the last Load.
+func (s *FSSource) Diagnostics() []Diagnostic {
+	return s.diagnostics
+}
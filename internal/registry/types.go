@@ -1,6 +1,11 @@
 package registry
 
-import "slices"
+import (
+	"io/fs"
+	"slices"
+	"strings"
+	"sync"
+)
 
 // Tool represents a supported AI coding tool.
 type Tool string
@@ -8,19 +13,53 @@ type Tool string
 const (
 	ToolOpenCode Tool = "opencode"
 	ToolClaude   Tool = "claude"
+	ToolCursor   Tool = "cursor"
+	ToolCopilot  Tool = "copilot"
+)
+
+var (
+	toolsMu sync.RWMutex
+
+	// extraTools are tools registered at runtime (e.g. declared in config.yaml).
+	extraTools []Tool
+
+	// toolAliases maps a registered tool to the tools whose items it also accepts.
+	toolAliases = make(map[Tool][]Tool)
 )
 
-// AllTools returns all supported tools.
+// BuiltinTools returns the tools supported out of the box.
+func BuiltinTools() []Tool {
+	return []Tool{ToolOpenCode, ToolClaude, ToolCursor, ToolCopilot}
+}
+
+// AllTools returns all supported tools, including registered ones.
 func AllTools() []Tool {
-	return []Tool{ToolOpenCode, ToolClaude}
+	toolsMu.RLock()
+	defer toolsMu.RUnlock()
+
+	return append(BuiltinTools(), extraTools...)
+}
+
+// RegisterTool makes an additional tool known.
+// Items compatible with any of compatibleWith are treated as compatible with the tool too.
+func RegisterTool(tool Tool, compatibleWith ...Tool) {
+	toolsMu.Lock()
+	defer toolsMu.Unlock()
+
+	if !slices.Contains(extraTools, tool) {
+		extraTools = append(extraTools, tool)
+	}
+
+	toolAliases[tool] = compatibleWith
 }
 
 // ItemType represents the type of registry item.
 type ItemType string
 
 const (
-	ItemTypeAgent ItemType = "agent"
-	ItemTypeSkill ItemType = "skill"
+	ItemTypeAgent  ItemType = "agent"
+	ItemTypeSkill  ItemType = "skill"
+	ItemTypeBundle ItemType = "bundle" // a named set of skills and agents, installed together
 )
 
 // ToolConfig contains tool-specific settings for an item.
@@ -31,6 +70,36 @@ type ToolConfig struct {
 	Bash  *bool `yaml:"bash,omitempty"`
 }
 
+// File is a resource bundled with a directory-form skill.
+type File struct {
+	// Path is the slash-separated path relative to the skill directory.
+	Path string
+
+	// Content is the raw file content.
+	Content []byte
+
+	// Mode holds the permission bits of the source file. Executable files stay executable when installed.
+	Mode fs.FileMode
+}
+
+// IsExecutable returns true if the source file has any executable bit set.
+func (f File) IsExecutable() bool {
+	return f.Mode&0o111 != 0
+}
+
+// Var is a template variable declared by an item.
+// Values are set under vars: in the project config or config.yaml.
+type Var struct {
+	// Description explains what the variable is for.
+	Description string `yaml:"description,omitempty"`
+
+	// Default is used when no value is set.
+	Default string `yaml:"default,omitempty"`
+
+	// Required variables must be set; their default is ignored.
+	Required bool `yaml:"required,omitempty"`
+}
+
 // Item represents a single installable item (agent or skill).
 type Item struct {
 	// Name is the identifier for this item.
@@ -51,9 +120,24 @@ type Item struct {
 	// Tools configuration (which tools are enabled/disabled).
 	Tools ToolConfig `yaml:"tools,omitempty"`
 
+	// Model is the preferred model for agents (e.g. "sonnet", "opus", "inherit"), where supported.
+	Model string `yaml:"model,omitempty"`
+
 	// Tags for filtering.
 	Tags []string `yaml:"tags,omitempty"`
 
+	// Requires lists items that must be installed along with this one, as [registry/]name[@ref]
+	// references. For bundles, these are the members of the bundle.
+	Requires []string `yaml:"requires,omitempty"`
+
+	// Vars declares the template variables used in the body, by name.
+	// Bodies of items that declare vars are rendered as Go text/template.
+	Vars map[string]Var `yaml:"vars,omitempty"`
+
+	// Globs limits the item to matching files in tools that support it
+	// (Cursor rule globs, Copilot instruction applyTo).
+	Globs []string `yaml:"globs,omitempty"`
+
 	// Author of this item.
 	Author string `yaml:"author,omitempty"`
 
@@ -66,21 +150,95 @@ type Item struct {
 	// Body is the content after frontmatter (the actual prompt/instructions).
 	Body string `yaml:"-"`
 
+	// Files are the resources bundled with a directory-form skill
+	// (scripts, templates, reference docs), sorted by path.
+	Files []File `yaml:"-"`
+
 	// SourcePath is the path to the source file (for debugging).
 	SourcePath string `yaml:"-"`
 
 	// Source is the name of the registry source this item came from.
 	Source string `yaml:"-"`
+
+	// Commit is the Git commit the item was loaded from (Git sources only).
+	Commit string `yaml:"-"`
 }
 
 // IsCompatibleWith checks if the item is compatible with a given tool.
 func (i *Item) IsCompatibleWith(tool Tool) bool {
-	return slices.Contains(i.Compatibility, tool)
+	if slices.Contains(i.Compatibility, tool) {
+		return true
+	}
+
+	toolsMu.RLock()
+	defer toolsMu.RUnlock()
+
+	for _, alias := range toolAliases[tool] {
+		if slices.Contains(i.Compatibility, alias) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // Registry holds all available items.
 type Registry struct {
-	Items []Item
+	Items       []Item
+	Diagnostics []Diagnostic // files that were skipped while loading
+
+	// Shadowed holds items hidden by a later source defining the same name, in load order.
+	Shadowed []Item
+}
+
+// Overridden returns the items shadowed by the item with the given name, in load order.
+// The first entry is the lowest-priority definition.
+func (r *Registry) Overridden(name string) []Item {
+	var result []Item
+
+	for _, item := range r.Shadowed {
+		if item.Name == name {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// Lookup finds an item by name or by a qualified "source/name" reference.
+// A plain name returns the winning definition; a qualified reference returns
+// the definition from that source, even if it is shadowed.
+func (r *Registry) Lookup(ref string) (*Item, bool) {
+	source, name := SplitRef(ref)
+
+	for i := range r.Items {
+		if r.Items[i].Name == name && (source == "" || r.Items[i].Source == source) {
+			return &r.Items[i], true
+		}
+	}
+
+	if source == "" {
+		return nil, false
+	}
+
+	for i := range r.Shadowed {
+		if r.Shadowed[i].Name == name && r.Shadowed[i].Source == source {
+			return &r.Shadowed[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// SplitRef splits a "source/name" reference. The source is empty for plain names.
+func SplitRef(ref string) (string, string) {
+	// Item names can't contain slashes, so the last one separates the source
+	idx := strings.LastIndex(ref, "/")
+	if idx < 0 {
+		return "", ref
+	}
+
+	return ref[:idx], ref[idx+1:]
 }
 
 // ByType returns items filtered by the specified type.
@@ -0,0 +1,132 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Limits from the agentskills.io specification.
+const (
+	MaxNameLength        = 64
+	MaxDescriptionLength = 1024
+)
+
+// Validation errors.
+var (
+	ErrMissingName         = errors.New("name is required")
+	ErrMissingDescription  = errors.New("description is required")
+	ErrInvalidName         = errors.New("invalid name")
+	ErrNameMismatch        = errors.New("name does not match the file name")
+	ErrDescriptionTooLong  = errors.New("description is too long")
+	ErrUnknownTool         = errors.New("unknown tool in compatibility")
+	ErrEmptyBody           = errors.New("body is empty")
+	ErrDuplicateName       = errors.New("duplicate name")
+	ErrEmptyBundle         = errors.New("bundle must require at least one item")
+	ErrInvalidVarName      = errors.New("invalid variable name")
+	errNameInvalidChars    = errors.New("must only contain lowercase letters, digits and hyphens")
+	errNameHyphenPlacement = errors.New("must not start or end with a hyphen or contain consecutive hyphens")
+)
+
+// ValidateName checks a name against the agentskills.io naming rules:
+// 1-64 lowercase letters, digits and single hyphens, not starting or ending with a hyphen.
+func ValidateName(name string) error {
+	if name == "" {
+		return ErrMissingName
+	}
+
+	if len(name) > MaxNameLength {
+		return fmt.Errorf("%w %q: must be at most %d characters", ErrInvalidName, name, MaxNameLength)
+	}
+
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return fmt.Errorf("%w %q: %w", ErrInvalidName, name, errNameInvalidChars)
+		}
+	}
+
+	if strings.HasPrefix(name, "-") || strings.HasSuffix(name, "-") || strings.Contains(name, "--") {
+		return fmt.Errorf("%w %q: %w", ErrInvalidName, name, errNameHyphenPlacement)
+	}
+
+	return nil
+}
+
+// ValidateItem checks a loaded item against the registry authoring rules.
+// All problems are returned, in a stable order.
+func ValidateItem(item *Item) []error {
+	var errs []error
+
+	err := ValidateName(item.Name)
+	if err != nil {
+		errs = append(errs, err)
+	} else if expected := NameFromPath(item.SourcePath); expected != "" && expected != item.Name {
+		errs = append(errs, fmt.Errorf("%w: name is %q, file is %q", ErrNameMismatch, item.Name, expected))
+	}
+
+	switch {
+	case strings.TrimSpace(item.Description) == "":
+		errs = append(errs, ErrMissingDescription)
+	case len(item.Description) > MaxDescriptionLength:
+		errs = append(errs, fmt.Errorf("%w: %d characters, at most %d allowed",
+			ErrDescriptionTooLong, len(item.Description), MaxDescriptionLength))
+	}
+
+	tools := AllTools()
+
+	for _, tool := range item.Compatibility {
+		if !slices.Contains(tools, tool) {
+			errs = append(errs, fmt.Errorf("%w: %q", ErrUnknownTool, tool))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(item.Vars)) {
+		if !isIdentifier(name) {
+			errs = append(errs, fmt.Errorf("%w %q: must be letters, digits and underscores, "+
+				"not starting with a digit", ErrInvalidVarName, name))
+		}
+	}
+
+	// Bundles only group other items, so they need members instead of a body
+	switch {
+	case item.Type == ItemTypeBundle && len(item.Requires) == 0:
+		errs = append(errs, ErrEmptyBundle)
+	case item.Type != ItemTypeBundle && item.Body == "":
+		errs = append(errs, ErrEmptyBody)
+	}
+
+	return errs
+}
+
+// isIdentifier reports whether s can be used as a template field name.
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i, r := range s {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_'
+		if !isLetter && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NameFromPath returns the item name implied by its source path:
+// the directory name for skills/<name>/SKILL.md, the file name without .md otherwise.
+func NameFromPath(p string) string {
+	if p == "" {
+		return ""
+	}
+
+	if path.Base(p) == skillFileName {
+		return path.Base(path.Dir(p))
+	}
+
+	return strings.TrimSuffix(path.Base(p), ".md")
+}
@@ -0,0 +1,182 @@
+// Package scaffold creates new agents and skills in a local registry from templates.
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/monke/skillsmith/internal/lint"
+	"github.com/monke/skillsmith/internal/registry"
+)
+
+var (
+	ErrExists         = errors.New("item already exists")
+	ErrDirFormAgent   = errors.New("only skills can use the directory form")
+	ErrInvalidNewItem = errors.New("generated item is invalid")
+)
+
+const (
+	dirPermissions  = 0o750
+	filePermissions = 0o644
+)
+
+// Options describes the item to create.
+type Options struct {
+	Type          registry.ItemType
+	Name          string
+	Description   string
+	Category      string
+	Compatibility []registry.Tool
+	Tags          []string
+
+	// Dir creates a directory-form skill (skills/<name>/SKILL.md) instead of a single file.
+	Dir bool
+}
+
+// Path returns the path of the main file of the item, relative to the registry root.
+func Path(opts Options) string {
+	if opts.Type == registry.ItemTypeAgent {
+		return filepath.Join("agents", opts.Name+".md")
+	}
+
+	if opts.Dir {
+		return filepath.Join("skills", opts.Name, "SKILL.md")
+	}
+
+	return filepath.Join("skills", opts.Name+".md")
+}
+
+// Create writes a new item into the registry at root and returns the path of the written file.
+// The rendered item is validated with the same rules as 'skillsmith lint' before it is written;
+// only problems lint reports as errors stop it from being written.
+func Create(root string, opts Options) (string, error) {
+	if opts.Dir && opts.Type == registry.ItemTypeAgent {
+		return "", ErrDirFormAgent
+	}
+
+	err := registry.ValidateName(opts.Name)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(root, Path(opts))
+
+	// Refuse to overwrite either form of an existing skill
+	for _, existing := range existingPaths(root, opts) {
+		if _, statErr := os.Stat(existing); statErr == nil {
+			return "", fmt.Errorf("%w: %s", ErrExists, existing)
+		}
+	}
+
+	content, err := render(opts)
+	if err != nil {
+		return "", err
+	}
+
+	err = validate(content, opts)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), dirPermissions)
+	if err != nil {
+		return "", fmt.Errorf("create directory: %w", err)
+	}
+
+	err = os.WriteFile(path, content, filePermissions) //nolint:gosec // registry files are meant to be shared
+	if err != nil {
+		return "", fmt.Errorf("write file: %w", err)
+	}
+
+	return path, nil
+}
+
+// existingPaths returns the paths that would conflict with the new item.
+func existingPaths(root string, opts Options) []string {
+	if opts.Type == registry.ItemTypeAgent {
+		return []string{filepath.Join(root, Path(opts))}
+	}
+
+	return []string{
+		filepath.Join(root, "skills", opts.Name+".md"),
+		filepath.Join(root, "skills", opts.Name),
+	}
+}
+
+// render renders the template for the item type.
+func render(opts Options) ([]byte, error) {
+	text := skillTemplate
+	if opts.Type == registry.ItemTypeAgent {
+		text = agentTemplate
+	}
+
+	tmpl, err := template.New(string(opts.Type)).Funcs(template.FuncMap{
+		"yaml":  yamlScalar,
+		"title": title,
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+
+	var buf bytes.Buffer
+
+	err = tmpl.Execute(&buf, opts)
+	if err != nil {
+		return nil, fmt.Errorf("render template: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// validate parses the rendered item and checks it like the loader and linter do.
+func validate(content []byte, opts Options) error {
+	item, err := registry.ParseItem(content)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidNewItem, err)
+	}
+
+	item.Type = opts.Type
+	item.SourcePath = filepath.ToSlash(Path(opts))
+
+	// Warnings, such as tools only declared in a consumer's config.yaml, are left to 'skillsmith lint'
+	var errs []error
+
+	for _, validationErr := range registry.ValidateItem(item) {
+		if lint.SeverityOf(validationErr) == lint.SeverityError {
+			errs = append(errs, validationErr)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidNewItem, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// yamlScalar encodes a value as a YAML scalar, quoting it if necessary.
+func yamlScalar(v any) (string, error) {
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("encode yaml: %w", err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// title turns an item name like "writing-go" into a heading like "Writing go".
+func title(name string) string {
+	words := strings.ReplaceAll(name, "-", " ")
+	if words == "" {
+		return ""
+	}
+
+	return strings.ToUpper(words[:1]) + words[1:]
+}
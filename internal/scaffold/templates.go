@@ -0,0 +1,72 @@
+package scaffold
+
+// agentTemplate is the template for new agents.
+// Agents start read-only; authors opt in to write, edit and bash.
+const agentTemplate = `---
+name: {{ .Name }}
+description: {{ yaml .Description }}
+{{- if .Category }}
+category: {{ yaml .Category }}
+{{- end }}
+compatibility:
+{{- range .Compatibility }}
+  - {{ . }}
+{{- end }}
+tools:
+  write: false
+  edit: false
+  bash: false
+{{- if .Tags }}
+tags:
+{{- range .Tags }}
+  - {{ yaml . }}
+{{- end }}
+{{- end }}
+---
+
+You are an agent that TODO: describe the role in one sentence. Focus on:
+
+- TODO: the first responsibility
+- TODO: the second responsibility
+
+## Guidelines
+
+- TODO: how to approach the work
+- TODO: what to report back
+`
+
+// skillTemplate is the template for new skills, in both single-file and directory form.
+const skillTemplate = `---
+name: {{ .Name }}
+description: {{ yaml .Description }}
+{{- if .Category }}
+category: {{ yaml .Category }}
+{{- end }}
+compatibility:
+{{- range .Compatibility }}
+  - {{ . }}
+{{- end }}
+{{- if .Tags }}
+tags:
+{{- range .Tags }}
+  - {{ yaml . }}
+{{- end }}
+{{- end }}
+---
+
+## {{ title .Name }}
+
+TODO: explain when this skill applies.
+
+## Instructions
+
+- TODO: the first instruction
+- TODO: the second instruction
+{{- if .Dir }}
+
+## Resources
+
+Files next to this SKILL.md are installed with the skill. Reference them by
+relative path, e.g. scripts/ or templates/.
+{{- end }}
+`
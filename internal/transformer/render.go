@@ -0,0 +1,62 @@
+package transformer
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/monke/skillsmith/internal/registry"
+)
+
+// ErrMissingVars is returned when required variables of an item have no value.
+var ErrMissingVars = errors.New("missing required variables")
+
+// Render renders the body of an item as a Go text/template with the given variable values.
+// Variables the item declares but that have no value fall back to their default.
+// Only items that declare vars are rendered, so other bodies may contain template syntax as-is.
+func Render(item registry.Item, vars map[string]string) (registry.Item, error) {
+	if len(item.Vars) == 0 {
+		return item, nil
+	}
+
+	data := make(map[string]string, len(item.Vars))
+
+	var missing []string
+
+	for _, name := range slices.Sorted(maps.Keys(item.Vars)) {
+		value, ok := vars[name]
+
+		switch {
+		case ok:
+			data[name] = value
+		case item.Vars[name].Required:
+			missing = append(missing, name)
+		default:
+			data[name] = item.Vars[name].Default
+		}
+	}
+
+	if len(missing) > 0 {
+		return item, fmt.Errorf("%w for %s: %s", ErrMissingVars, item.Name, strings.Join(missing, ", "))
+	}
+
+	// Referencing a variable the item doesn't declare is an error
+	tmpl, err := template.New(item.Name).Option("missingkey=error").Parse(item.Body)
+	if err != nil {
+		return item, fmt.Errorf("parse body template: %w", err)
+	}
+
+	var sb strings.Builder
+
+	err = tmpl.Execute(&sb, data)
+	if err != nil {
+		return item, fmt.Errorf("render body template: %w", err)
+	}
+
+	item.Body = sb.String()
+
+	return item, nil
+}
@@ -1,7 +1,7 @@
+// Package transformer renders registry items in the file formats of the builtin tools.
 package transformer
 
 import (
-	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -9,23 +9,9 @@ import (
 	"github.com/monke/skillsmith/internal/registry"
 )
 
-var errUnsupportedTool = errors.New("unsupported tool")
-
-// Transform converts a generic registry item to tool-specific content.
-func Transform(item registry.Item, tool registry.Tool) (string, error) {
-	switch tool {
-	case registry.ToolOpenCode:
-		return transformOpenCode(item), nil
-	case registry.ToolClaude:
-		return transformClaude(item), nil
-	default:
-		return "", fmt.Errorf("%w: %s", errUnsupportedTool, tool)
-	}
-}
-
-// transformOpenCode converts an item to OpenCode format.
+// OpenCode converts an item to OpenCode format.
 // Skills and agents have different frontmatter requirements per the agentskills.io spec.
-func transformOpenCode(item registry.Item) string {
+func OpenCode(item registry.Item) string {
 	var sb strings.Builder
 
 	// Write frontmatter
@@ -91,9 +77,14 @@ func transformOpenCode(item registry.Item) string {
 	return sb.String()
 }
 
-// transformClaude converts an item to Claude Code format.
+// Claude converts an item to Claude Code format.
 // For skills, this creates a SKILL.md with the appropriate frontmatter per the agentskills.io spec.
-func transformClaude(item registry.Item) string {
+// Agents become subagents with a tool allow-list and optional model.
+func Claude(item registry.Item) string {
+	if item.Type == registry.ItemTypeAgent {
+		return claudeAgent(item)
+	}
+
 	var sb strings.Builder
 
 	// Write frontmatter
@@ -134,3 +125,126 @@ func transformClaude(item registry.Item) string {
 
 	return sb.String()
 }
+
+// claudeAgent converts an agent to a Claude Code subagent.
+func claudeAgent(item registry.Item) string {
+	var sb strings.Builder
+
+	// Write frontmatter
+	sb.WriteString("---\n")
+	sb.WriteString(fmt.Sprintf("name: %s\n", item.Name))
+	sb.WriteString(fmt.Sprintf("description: %s\n", item.Description))
+
+	// Omitting tools lets the subagent inherit all tools
+	if tools := claudeTools(item.Tools); len(tools) > 0 {
+		sb.WriteString(fmt.Sprintf("tools: %s\n", strings.Join(tools, ", ")))
+	}
+
+	if item.Model != "" {
+		sb.WriteString(fmt.Sprintf("model: %s\n", item.Model))
+	}
+
+	sb.WriteString("---\n\n")
+
+	// Write body
+	sb.WriteString(item.Body)
+
+	return sb.String()
+}
+
+// claudeTools translates an item's tool settings to a Claude Code tool allow-list.
+// Returns nil if the item doesn't restrict any tool.
+func claudeTools(cfg registry.ToolConfig) []string {
+	if cfg.Write == nil && cfg.Edit == nil && cfg.Bash == nil {
+		return nil
+	}
+
+	tools := []string{"Read", "Grep", "Glob"}
+
+	if isEnabled(cfg.Write) {
+		tools = append(tools, "Write")
+	}
+
+	if isEnabled(cfg.Edit) {
+		tools = append(tools, "Edit")
+	}
+
+	if isEnabled(cfg.Bash) {
+		tools = append(tools, "Bash")
+	}
+
+	return tools
+}
+
+// Cursor converts an item to a Cursor rule (.mdc).
+// Rules without globs are "agent requested": Cursor decides when to apply them based on the description.
+func Cursor(item registry.Item) string {
+	var sb strings.Builder
+
+	// Write frontmatter
+	sb.WriteString("---\n")
+	sb.WriteString(fmt.Sprintf("description: %s\n", item.Description))
+
+	if len(item.Globs) > 0 {
+		sb.WriteString(fmt.Sprintf("globs: %s\n", strings.Join(item.Globs, ",")))
+	} else {
+		sb.WriteString("globs:\n")
+	}
+
+	sb.WriteString("alwaysApply: false\n")
+	sb.WriteString("---\n\n")
+
+	// Write body
+	sb.WriteString(item.Body)
+
+	return sb.String()
+}
+
+// Copilot converts an item to GitHub Copilot format.
+// Skills become path-specific instructions (.instructions.md), agents become custom agents (.agent.md).
+func Copilot(item registry.Item) string {
+	var sb strings.Builder
+
+	// Write frontmatter
+	sb.WriteString("---\n")
+
+	if item.Type == registry.ItemTypeAgent {
+		sb.WriteString(fmt.Sprintf("name: %s\n", item.Name))
+		sb.WriteString(fmt.Sprintf("description: %s\n", item.Description))
+
+		// Restrict tools only if the item configures any
+		if item.Tools.Write != nil || item.Tools.Edit != nil || item.Tools.Bash != nil {
+			tools := []string{"read", "search"}
+
+			if isEnabled(item.Tools.Write) && isEnabled(item.Tools.Edit) {
+				tools = append(tools, "edit")
+			}
+
+			if isEnabled(item.Tools.Bash) {
+				tools = append(tools, "shell")
+			}
+
+			sb.WriteString(fmt.Sprintf("tools: [%s]\n", strings.Join(tools, ", ")))
+		}
+	} else {
+		applyTo := "**"
+		if len(item.Globs) > 0 {
+			applyTo = strings.Join(item.Globs, ",")
+		}
+
+		sb.WriteString(fmt.Sprintf("description: %s\n", item.Description))
+		sb.WriteString(fmt.Sprintf("applyTo: %q\n", applyTo))
+	}
+
+	sb.WriteString("---\n\n")
+
+	// Write body
+	sb.WriteString(item.Body)
+
+	return sb.String()
+}
+
+// isEnabled returns true unless a tool setting is explicitly disabled.
+func isEnabled(setting *bool) bool {
+	return setting == nil || *setting
+}
@@ -16,6 +16,7 @@ const (
 	ScreenScopeSelect
 	ScreenBrowser
 	ScreenActionMenu
+	ScreenDiff
 )
 
 // KeyMap defines all keyboard shortcuts.
@@ -27,6 +28,9 @@ type KeyMap struct {
 	SelectAll   key.Binding
 	DeselectAll key.Binding
 	UpdateAll   key.Binding
+	Diff        key.Binding
+	PageUp      key.Binding
+	PageDown    key.Binding
 	Back        key.Binding
 	Quit        key.Binding
 }
@@ -53,6 +57,15 @@ var keys = KeyMap{
 	UpdateAll: key.NewBinding(
 		key.WithKeys("u"),
 	),
+	Diff: key.NewBinding(
+		key.WithKeys("v"),
+	),
+	PageUp: key.NewBinding(
+		key.WithKeys("pgup"),
+	),
+	PageDown: key.NewBinding(
+		key.WithKeys("pgdown"),
+	),
 	Back: key.NewBinding(
 		key.WithKeys("esc"),
 	),
@@ -94,6 +107,13 @@ type BrowserState struct {
 	Offset int // scroll offset for visible window
 }
 
+// DiffState holds state for the diff screen.
+type DiffState struct {
+	Name   string   // item being compared
+	Lines  []string // unified diff lines
+	Offset int      // scroll offset
+}
+
 // ActionMenuState holds state for the action menu screen.
 type ActionMenuState struct {
 	Options []MenuOption
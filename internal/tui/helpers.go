@@ -2,6 +2,7 @@ package tui
 
 import (
 	"github.com/monke/skillsmith/internal/config"
+	"github.com/monke/skillsmith/internal/installer"
 	"github.com/monke/skillsmith/internal/registry"
 )
 
@@ -79,6 +80,22 @@ func (m *Model) countInstalledForTool(tool registry.Tool, scope config.Scope) (i
 	return installed, updates
 }
 
+// refreshStatuses re-reads the installation state of the browser items.
+func (m *Model) refreshStatuses() {
+	items := m.mgr.ListItemsWithState(m.selectedTool, m.selectedScope, "")
+	states := make(map[string]installer.ItemState, len(items))
+
+	for _, item := range items {
+		states[item.Item.Name] = item.State
+	}
+
+	for i, bi := range m.browser.Items {
+		if state, ok := states[bi.Item.Name]; ok {
+			m.browser.Items[i].Status = state
+		}
+	}
+}
+
 // getScopeLabel returns the display label for the current scope.
 func (m *Model) getScopeLabel() string {
 	if m.selectedScope == config.ScopeGlobal {
@@ -127,6 +144,10 @@ func (m *Model) getItemsForAction(action string) []BrowserItem {
 			if bi.Status.IsInstalled() {
 				items = append(items, bi)
 			}
+		case ActionMerge:
+			if bi.Status == installer.StateModifiedWithUpdate {
+				items = append(items, bi)
+			}
 		}
 	}
 
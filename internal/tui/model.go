@@ -27,6 +27,7 @@ type Model struct {
 	scopeSelect ScopeSelectState
 	browser     BrowserState
 	actionMenu  ActionMenuState
+	diff        DiffState
 
 	// Messages
 	message      string
@@ -79,6 +80,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateBrowser(msg)
 		case ScreenActionMenu:
 			return m.updateActionMenu(msg)
+		case ScreenDiff:
+			return m.updateDiff(msg)
 		}
 	}
 
@@ -100,6 +103,8 @@ func (m *Model) View() string {
 		return m.viewBrowser()
 	case ScreenActionMenu:
 		return m.viewActionMenu()
+	case ScreenDiff:
+		return m.viewDiff()
 	default:
 		return "Unknown screen"
 	}
@@ -44,6 +44,15 @@ func (m *Model) buildMenuOptions() {
 			Enabled: true,
 		})
 
+		// Modified items are skipped by Update, so offer to merge the update into them
+		if mergeCount := len(m.getItemsForAction(ActionMerge)); mergeCount > 0 {
+			m.actionMenu.Options = append(m.actionMenu.Options, MenuOption{
+				Label:   fmt.Sprintf("Merge update (%d modified)", mergeCount),
+				Action:  ActionMerge,
+				Enabled: true,
+			})
+		}
+
 		m.actionMenu.Options = append(m.actionMenu.Options, MenuOption{
 			Label:   fmt.Sprintf("Uninstall (%d)", installedCount),
 			Action:  ActionUninstall,
@@ -105,6 +114,8 @@ func (m *Model) executeMenuAction() {
 		m.installNew()
 	case ActionUpdate:
 		m.updateInstalled()
+	case ActionMerge:
+		m.mergeSelected()
 	case ActionUninstall:
 		m.uninstallSelected()
 	}
@@ -124,6 +135,7 @@ func (m *Model) installNew() {
 			m.message = fmt.Sprintf("Error: %v", err)
 			m.messageStyle = errorMsgStyle
 			m.screen = ScreenBrowser
+			m.refreshStatuses()
 
 			return
 		}
@@ -141,6 +153,10 @@ func (m *Model) installNew() {
 		m.messageStyle = successMsgStyle
 	}
 
+	// Requirements may have been installed along with the selected items
+	m.refreshStatuses()
+	m.showWarnings()
+
 	m.screen = ScreenBrowser
 }
 
@@ -190,6 +206,55 @@ func (m *Model) updateInstalled() {
 		m.messageStyle = modifiedStyle
 	}
 
+	m.showWarnings()
+
+	m.screen = ScreenBrowser
+}
+
+// mergeSelected merges updates into the selected locally modified items.
+func (m *Model) mergeSelected() {
+	merged := 0
+	conflicted := 0
+
+	for i, bi := range m.browser.Items {
+		if !bi.Selected || bi.Status != installer.StateModifiedWithUpdate {
+			continue
+		}
+
+		result, path, err := m.mgr.Merge(bi.Item.Name, m.selectedTool, m.selectedScope)
+		if err != nil {
+			m.message = fmt.Sprintf("Error: %v", err)
+			m.messageStyle = errorMsgStyle
+			m.screen = ScreenBrowser
+			m.refreshStatuses()
+
+			return
+		}
+
+		merged++
+
+		if result.Conflicts > 0 {
+			conflicted++
+			m.message = fmt.Sprintf("Conflicts to resolve in %s", path)
+		}
+
+		m.browser.Items[i].Selected = false
+	}
+
+	m.refreshStatuses()
+
+	switch {
+	case conflicted == 1 && merged == 1:
+		// Keep the message naming the file
+		m.messageStyle = modifiedStyle
+	case conflicted > 0:
+		m.message = fmt.Sprintf("Merged %d items, %d with conflicts to resolve", merged, conflicted)
+		m.messageStyle = modifiedStyle
+	case merged > 0:
+		m.message = fmt.Sprintf("Merged %d items", merged)
+		m.messageStyle = successMsgStyle
+	}
+
 	m.screen = ScreenBrowser
 }
 
@@ -239,6 +304,23 @@ func (m *Model) updateAllInstalled() {
 		m.message = "No installed items to update"
 		m.messageStyle = dimStyle
 	}
+
+	m.showWarnings()
+}
+
+// showWarnings replaces the message with the problems that didn't stop the last operation, if any.
+func (m *Model) showWarnings() {
+	warnings := m.mgr.Warnings()
+	if len(warnings) == 0 {
+		return
+	}
+
+	m.message = fmt.Sprintf("Warning: %v", warnings[0])
+	if len(warnings) > 1 {
+		m.message += fmt.Sprintf(" (and %d more)", len(warnings)-1)
+	}
+
+	m.messageStyle = modifiedStyle
 }
 
 // uninstallSelected removes all selected installed items.
@@ -357,6 +439,8 @@ func (m *Model) renderActionPreview(_, maxHeight int) string {
 			title = "Will Install"
 		case ActionUpdate:
 			title = "Will Update"
+		case ActionMerge:
+			title = "Will Merge"
 		case ActionUninstall:
 			title = "Will Uninstall"
 		}
@@ -55,6 +55,8 @@ func (m *Model) updateBrowser(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	case key.Matches(msg, keys.UpdateAll):
 		m.updateAllInstalled()
+	case key.Matches(msg, keys.Diff):
+		m.openDiff()
 	case key.Matches(msg, keys.Enter):
 		m.openActionMenu()
 	case key.Matches(msg, keys.Back):
@@ -118,16 +120,20 @@ func (m *Model) viewBrowser() string {
 
 	selected, installedCount, newCount := m.countSelected()
 
-	if selected > 0 {
+	// The outcome of the last action replaces the selection status until the next key press
+	switch {
+	case m.message != "":
+		footer.WriteString(m.messageStyle.Render(m.message))
+	case selected > 0:
 		status := fmt.Sprintf("%d selected (%d installed, %d new)", selected, installedCount, newCount)
 		footer.WriteString(normalStyle.Render(status))
-	} else {
+	default:
 		footer.WriteString(dimStyle.Render("No items selected"))
 	}
 
 	footer.WriteString("\n\n")
 
-	helpText := "[space] toggle  [a/d] all/none  [u] update  [enter] actions  [esc] back  [q] quit"
+	helpText := "[space] toggle  [a/d] all/none  [u] update  [v] diff  [enter] actions  [esc] back  [q] quit"
 	footer.WriteString(helpStyle.Render(helpText))
 
 	return m.renderLayout(header.String(), content, footer.String())
@@ -309,6 +315,11 @@ func (m *Model) renderPreviewMetadata(sb *strings.Builder, bi BrowserItem) {
 		sb.WriteString(accentStyle.Render(source))
 	}
 
+	// Explain which definitions this one hides, highest priority first
+	if overrides := m.mgr.OverriddenSources(bi.Item.Name); len(overrides) > 0 {
+		sb.WriteString(updateStyle.Render(" (overrides " + strings.Join(overrides, ", ") + ")"))
+	}
+
 	sb.WriteString("\n")
 
 	// Type
@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// diffOverhead is the number of lines used by the header, footer and margins of the diff screen.
+const diffOverhead = 6
+
+// openDiff shows the diff between the installed file of the item under the cursor and the registry.
+func (m *Model) openDiff() {
+	if m.browser.Cursor >= len(m.browser.Items) {
+		return
+	}
+
+	bi := m.browser.Items[m.browser.Cursor]
+
+	if !bi.Status.IsInstalled() {
+		m.message = fmt.Sprintf("%s is not installed, nothing to compare", bi.Item.Name)
+		m.messageStyle = dimStyle
+
+		return
+	}
+
+	text, err := m.mgr.Diff(bi.Item.Name, m.selectedTool, m.selectedScope)
+	if err != nil {
+		m.message = fmt.Sprintf("Error: %v", err)
+		m.messageStyle = errorMsgStyle
+
+		return
+	}
+
+	if text == "" {
+		m.message = fmt.Sprintf("%s matches the registry version", bi.Item.Name)
+		m.messageStyle = successMsgStyle
+
+		return
+	}
+
+	m.diff = DiffState{
+		Name:  bi.Item.Name,
+		Lines: strings.Split(strings.TrimSuffix(text, "\n"), "\n"),
+	}
+	m.screen = ScreenDiff
+}
+
+// updateDiff handles input for the diff screen.
+func (m *Model) updateDiff(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	maxOffset := max(len(m.diff.Lines)-m.diffVisibleLines(), 0)
+
+	switch {
+	case key.Matches(msg, keys.Up):
+		m.diff.Offset = max(m.diff.Offset-1, 0)
+	case key.Matches(msg, keys.Down):
+		m.diff.Offset = min(m.diff.Offset+1, maxOffset)
+	case key.Matches(msg, keys.PageUp):
+		m.diff.Offset = max(m.diff.Offset-m.diffVisibleLines(), 0)
+	case key.Matches(msg, keys.PageDown):
+		m.diff.Offset = min(m.diff.Offset+m.diffVisibleLines(), maxOffset)
+	case key.Matches(msg, keys.Back):
+		m.screen = ScreenBrowser
+	}
+
+	return m, nil
+}
+
+// diffVisibleLines returns how many diff lines fit on the screen.
+func (m *Model) diffVisibleLines() int {
+	return max(m.height-diffOverhead, minVisibleItems)
+}
+
+// viewDiff renders the diff screen.
+func (m *Model) viewDiff() string {
+	var header strings.Builder
+
+	header.WriteString(titleStyle.Render("skillsmith"))
+	header.WriteString(accentStyle.Render(" > "))
+	header.WriteString(normalStyle.Render(string(m.selectedTool)))
+	header.WriteString(accentStyle.Render(" > "))
+	header.WriteString(normalStyle.Render(m.getScopeLabel()))
+	header.WriteString(accentStyle.Render(" > "))
+	header.WriteString(normalStyle.Render("diff " + m.diff.Name))
+
+	var content strings.Builder
+
+	leftPad := strings.Repeat(" ", mainLeftPadding)
+	width := m.width - mainLeftPaddingTotal
+	end := min(m.diff.Offset+m.diffVisibleLines(), len(m.diff.Lines))
+
+	for i := m.diff.Offset; i < end; i++ {
+		content.WriteString(leftPad)
+		content.WriteString(renderDiffLine(m.diff.Lines[i], width))
+		content.WriteString("\n")
+	}
+
+	position := fmt.Sprintf("lines %d-%d of %d", m.diff.Offset+1, end, len(m.diff.Lines))
+	footer := dimStyle.Render(position) + "\n\n" +
+		helpStyle.Render("[up/down] scroll  [pgup/pgdn] page  [esc] back  [q] quit")
+
+	return m.renderLayout(header.String(), content.String(), footer)
+}
+
+// renderDiffLine truncates a unified diff line to width and colours it by its prefix.
+func renderDiffLine(line string, width int) string {
+	if runes := []rune(line); width > 0 && len(runes) > width {
+		line = string(runes[:width])
+	}
+
+	switch {
+	case strings.HasPrefix(line, "--- "), strings.HasPrefix(line, "+++ "):
+		return headerStyle.Render(line)
+	case strings.HasPrefix(line, "@@"):
+		return accentStyle.Render(line)
+	case strings.HasPrefix(line, "-"):
+		return errorMsgStyle.Render(line)
+	case strings.HasPrefix(line, "+"):
+		return installedStyle.Render(line)
+	default:
+		return normalStyle.Render(line)
+	}
+}
@@ -44,6 +44,8 @@ func (m *Model) viewToolSelect() string {
 		m.renderToolOption(&content, i, tool)
 	}
 
+	m.renderSourceWarning(&content)
+
 	footer := helpStyle.Render("[enter] select  [q] quit")
 	paddedContent := lipgloss.NewStyle().
 		MarginLeft(mainLeftPadding).
@@ -80,6 +82,27 @@ func (m *Model) renderToolOption(content *strings.Builder, idx int, tool registr
 	content.WriteString("\n")
 }
 
+// renderSourceWarning renders a warning banner if any registry source failed to load.
+func (m *Model) renderSourceWarning(content *strings.Builder) {
+	failed := m.mgr.FailedSources()
+	if len(failed) == 0 {
+		return
+	}
+
+	content.WriteString("\n")
+	content.WriteString(warningStyle.Render(fmt.Sprintf("! %d registry source(s) failed to load:", len(failed))))
+	content.WriteString("\n")
+
+	for _, status := range failed {
+		msg := wrapText(fmt.Sprintf("%s: %v", status.Name, status.Error), m.width-mainLeftPaddingTotal-mainLeftPadding)
+		content.WriteString(dimStyle.PaddingLeft(mainLeftPadding).Render(msg))
+		content.WriteString("\n")
+	}
+
+	content.WriteString(dimStyle.Render("  Run 'skillsmith registry list' for details."))
+	content.WriteString("\n")
+}
+
 // countItemTypesForTool returns the count of agents and skills for a tool.
 func (m *Model) countItemTypesForTool(tool registry.Tool) (int, int) {
 	items := m.mgr.ListItemsWithState(tool, config.ScopeLocal, "")
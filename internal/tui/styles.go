@@ -26,6 +26,7 @@ const (
 const (
 	ActionInstall   = "install"
 	ActionUpdate    = "update"
+	ActionMerge     = "merge"
 	ActionUninstall = "uninstall"
 )
 
@@ -76,6 +77,10 @@ var (
 	errorMsgStyle = lipgloss.NewStyle().
 			Foreground(red)
 
+	warningStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(yellow)
+
 	successMsgStyle = lipgloss.NewStyle().
 			Foreground(green)
 